@@ -0,0 +1,165 @@
+// Command gen scaffolds a new API resource following the layout of items:
+//
+//	go run ./cmd/gen resource [-plural people] person
+//
+// It writes a migration, a db repository, the handlers and an OpenAPI
+// fragment, then prints the steps left to do by hand. Existing files are
+// never overwritten.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// resourceName restricts names to what maps cleanly onto Go identifiers,
+// SQL table names and the operation names oapi-codegen derives from paths.
+var resourceName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// reserved names would shadow packages the generated code imports, or
+// clash with the item code the templates are modelled on. The templates
+// only use the name inside longer identifiers, never as a local variable.
+var reserved = map[string]bool{"db": true, "models": true, "problem": true, "gin": true, "http": true, "context": true, "strings": true, "item": true}
+
+// Resource is the data the templates are executed with.
+type Resource struct {
+	Name       string // singular, e.g. "widget"
+	Type       string // Go type, e.g. "Widget"
+	Plural     string // table and path segment, e.g. "widgets"
+	PluralType string // e.g. "Widgets", as in GetWidgets
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 || os.Args[1] != "resource" {
+		log.Fatal("usage: gen resource [-plural name] [-root dir] <name>")
+	}
+	flags := flag.NewFlagSet("resource", flag.ExitOnError)
+	plural := flags.String("plural", "", `plural used for the table and paths (default: name + "s")`)
+	root := flags.String("root", ".", "repository root")
+	flags.Parse(os.Args[2:])
+	if flags.NArg() != 1 {
+		log.Fatal("usage: gen resource [-plural name] [-root dir] <name>")
+	}
+
+	r, err := newResource(flags.Arg(0), *plural)
+	if err != nil {
+		log.Fatal(err)
+	}
+	created, err := scaffold(*root, r)
+	for _, path := range created {
+		fmt.Println("created", path)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf(`
+Next steps:
+  1. Run "task generate" to regenerate models and routes; the fragment in
+     openapi/resources is merged into the spec automatically.
+  2. Apply the new migration, then extend the %s schema, migration and
+     repository with the resource's own fields.
+`, r.Type)
+}
+
+func newResource(name, plural string) (Resource, error) {
+	if plural == "" {
+		plural = name + "s"
+	}
+	for _, n := range []string{name, plural} {
+		if !resourceName.MatchString(n) || token.IsKeyword(n) || reserved[n] {
+			return Resource{}, fmt.Errorf("invalid resource name %q: use lowercase letters and digits, not a Go keyword or imported package", n)
+		}
+	}
+	if name == plural {
+		return Resource{}, fmt.Errorf("plural of %q must differ from the name", name)
+	}
+	return Resource{
+		Name:       name,
+		Type:       strings.ToUpper(name[:1]) + name[1:],
+		Plural:     plural,
+		PluralType: strings.ToUpper(plural[:1]) + plural[1:],
+	}, nil
+}
+
+// scaffold writes the files of resource r under root and returns their
+// paths relative to root. It fails before writing anything if one of them
+// already exists.
+func scaffold(root string, r Resource) ([]string, error) {
+	migration, err := nextMigration(filepath.Join(root, "db", "migrations"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct{ template, path string }{
+		{"migration.sql.tmpl", filepath.Join("db", "migrations", fmt.Sprintf("%04d_create_%s.sql", migration, r.Plural))},
+		{"repository.go.tmpl", filepath.Join("db", r.Plural+".go")},
+		{"handlers.go.tmpl", filepath.Join("handlers", r.Plural+".go")},
+		{"spec.yaml.tmpl", filepath.Join("openapi", "resources", r.Plural+".yaml")},
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(root, f.path)); err == nil {
+			return nil, fmt.Errorf("%s already exists", f.path)
+		}
+	}
+	var created []string
+	for _, f := range files {
+		if err := render(filepath.Join(root, f.path), f.template, r); err != nil {
+			return created, err
+		}
+		created = append(created, f.path)
+	}
+	return created, nil
+}
+
+// nextMigration returns the number following the highest numbered
+// migration in dir.
+func nextMigration(dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return 0, err
+	}
+	highest := 0
+	for _, path := range paths {
+		prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+		if n, err := strconv.Atoi(prefix); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest + 1, nil
+}
+
+func render(path, name string, r Resource) error {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, r); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	if strings.HasSuffix(path, ".go") {
+		formatted, err := format.Source(out)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out = formatted
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
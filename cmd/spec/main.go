@@ -0,0 +1,109 @@
+// Command spec writes the merged OpenAPI document, openapi.yaml plus the
+// fragments in openapi/resources, for the code generators:
+//
+//	go run ./cmd/spec -o openapi/build/openapi.yaml
+//
+// With -check it instead compares the merged document with a released one
+// and fails on breaking changes not listed in the -ack file:
+//
+//	go run ./cmd/spec -check openapi/released/openapi.yaml -ack openapi/released/acknowledged.txt
+//
+// After a release, refresh the snapshot with -o and empty the ack file.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sample/openapi"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func main() {
+	log.SetFlags(0)
+	out := flag.String("o", "", "output file (default: standard output)")
+	check := flag.String("check", "", "released spec to check the current one against")
+	ack := flag.String("ack", "", "file listing acknowledged breaking changes, one per line")
+	flag.Parse()
+
+	data, err := openapi.Merged()
+	if err != nil {
+		log.Fatal(err)
+	}
+	current, err := openapi.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *check != "" {
+		os.Exit(checkCompatibility(*check, *ack, current))
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// checkCompatibility prints the breaking changes since the released spec
+// and returns the exit status: 1 if any of them is not acknowledged.
+func checkCompatibility(releasedPath, ackPath string, current *openapi3.T) int {
+	released, err := openapi3.NewLoader().LoadFromFile(releasedPath)
+	if err != nil {
+		log.Fatalf("load released spec: %v", err)
+	}
+	acknowledged := map[string]bool{}
+	if ackPath != "" {
+		if acknowledged, err = readAcknowledged(ackPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	status := 0
+	for _, change := range openapi.Breaking(released, current) {
+		if acknowledged[change] {
+			fmt.Println("acknowledged:", change)
+			continue
+		}
+		fmt.Println("BREAKING:", change)
+		status = 1
+	}
+	if status != 0 {
+		fmt.Fprintf(os.Stderr, "\nbreaking changes since %s; if they are intended, add the lines after \"BREAKING: \" to the ack file\n", releasedPath)
+	}
+	return status
+}
+
+// readAcknowledged reads the ack file. Blank lines and lines starting with
+// # are ignored.
+func readAcknowledged(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	acknowledged := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			acknowledged[line] = true
+		}
+	}
+	return acknowledged, scanner.Err()
+}
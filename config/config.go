@@ -0,0 +1,291 @@
+// Package config loads the service settings. Defaults are overridden by an
+// optional YAML file, named by CONFIG_FILE, and then by environment
+// variables, so a deployment can keep a shared file and still adjust single
+// values per instance. config.example.yaml documents every setting.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the service.
+type Config struct {
+	// LogLevel is "info" or "debug"; debug also switches Gin to debug mode,
+	// which logs the registered routes.
+	LogLevel string `yaml:"log_level"`
+	// RequestParsingMode is "strict" or "lenient"; see handlers.ParsingMode.
+	RequestParsingMode string `yaml:"request_parsing_mode"`
+	// ValidateResponses checks responses against the OpenAPI spec and logs
+	// any drift. It buffers every response, so keep it to development.
+	ValidateResponses bool `yaml:"validate_responses"`
+	// ItemDescriptionTemplate is the text/template that fills in the
+	// description of items created without one.
+	ItemDescriptionTemplate string `yaml:"item_description_template"`
+
+	HTTP     Listener `yaml:"http"`
+	Admin    Listener `yaml:"admin"`
+	Database Database `yaml:"database"`
+	Search   Search   `yaml:"search"`
+}
+
+// Listener configures one HTTP server. An empty Addr turns it off; TLS is
+// used when both certificate files are set.
+type Listener struct {
+	Addr        string `yaml:"addr"`
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+}
+
+// Database configures the connection pool and the db package.
+type Database struct {
+	// DSN is a postgres:// URL.
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+
+	// StatementTimeout and IdleInTransactionTimeout are sent as the
+	// session settings of the same name; zero keeps the server's.
+	StatementTimeout         time.Duration `yaml:"statement_timeout"`
+	IdleInTransactionTimeout time.Duration `yaml:"idle_in_transaction_timeout"`
+
+	LogStatements      bool          `yaml:"log_statements"`
+	LogRedactColumns   []string      `yaml:"log_redact_columns"`
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	ExplainSlowQueries bool          `yaml:"explain_slow_queries"`
+
+	StatementBudget        int64   `yaml:"statement_budget"`
+	EnforceStatementBudget bool    `yaml:"statement_budget_enforce"`
+	FilterScanRowLimit     float64 `yaml:"filter_scan_row_limit"`
+	UUIDPrimaryKeys        bool    `yaml:"uuid_primary_keys"`
+}
+
+// Search configures the headline excerpts of search results; the fields
+// map onto ts_headline's options of the same name.
+type Search struct {
+	Highlight Highlight `yaml:"highlight"`
+}
+
+// Highlight mirrors db.Highlight, which it is converted to.
+type Highlight struct {
+	StartSel          string `yaml:"start_sel"`
+	StopSel           string `yaml:"stop_sel"`
+	MaxWords          int    `yaml:"max_words"`
+	MinWords          int    `yaml:"min_words"`
+	MaxFragments      int    `yaml:"max_fragments"`
+	FragmentDelimiter string `yaml:"fragment_delimiter"`
+}
+
+// Default returns the settings used when nothing overrides them: a local
+// database without a password, the public listener on :8080 and the admin
+// listener on the loopback interface only, port 8081. Deployments whose
+// health probes come from outside the host set admin.addr to reach it.
+func Default() Config {
+	return Config{
+		LogLevel: "info",
+		HTTP:     Listener{Addr: ":8080"},
+		Admin:    Listener{Addr: "127.0.0.1:8081"},
+		Database: Database{
+			DSN:             "postgres://postgres@localhost/openapi-go-crud?sslmode=disable",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Search: Search{Highlight: Highlight{
+			StartSel: "<b>",
+			StopSel:  "</b>",
+			MaxWords: 35,
+			MinWords: 15,
+		}},
+	}
+}
+
+// Load returns Default overridden by the file named by CONFIG_FILE, if
+// any, and then by the environment, and validates the result.
+func Load() (Config, error) {
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return cfg, err
+		}
+	}
+	if err := cfg.readEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// readEnv applies the environment variables that are set. An empty value
+// counts as set, so HTTP_ADDR= or ADMIN_ADDR= turns a listener off.
+func (c *Config) readEnv() error {
+	var e env
+	e.string("LOG_LEVEL", &c.LogLevel)
+	e.string("REQUEST_PARSING_MODE", &c.RequestParsingMode)
+	e.bool("OPENAPI_VALIDATE_RESPONSES", &c.ValidateResponses)
+	e.string("ITEM_DESCRIPTION_TEMPLATE", &c.ItemDescriptionTemplate)
+
+	for prefix, l := range map[string]*Listener{"HTTP": &c.HTTP, "ADMIN": &c.Admin} {
+		e.string(prefix+"_ADDR", &l.Addr)
+		e.string(prefix+"_TLS_CERT_FILE", &l.TLSCertFile)
+		e.string(prefix+"_TLS_KEY_FILE", &l.TLSKeyFile)
+	}
+
+	d := &c.Database
+	e.string("DB_DSN", &d.DSN)
+	e.int("DB_MAX_OPEN_CONNS", &d.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &d.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &d.ConnMaxLifetime)
+	e.duration("DB_STATEMENT_TIMEOUT", &d.StatementTimeout)
+	e.duration("DB_IDLE_IN_TRANSACTION_TIMEOUT", &d.IdleInTransactionTimeout)
+	e.bool("DB_LOG_STATEMENTS", &d.LogStatements)
+	e.list("DB_LOG_REDACT_COLUMNS", &d.LogRedactColumns)
+	e.duration("DB_SLOW_QUERY_THRESHOLD", &d.SlowQueryThreshold)
+	e.bool("DB_EXPLAIN_SLOW_QUERIES", &d.ExplainSlowQueries)
+	e.int64("DB_STATEMENT_BUDGET", &d.StatementBudget)
+	e.bool("DB_STATEMENT_BUDGET_ENFORCE", &d.EnforceStatementBudget)
+	e.float("DB_FILTER_SCAN_ROW_LIMIT", &d.FilterScanRowLimit)
+	e.bool("DB_UUID_PRIMARY_KEYS", &d.UUIDPrimaryKeys)
+
+	h := &c.Search.Highlight
+	e.string("SEARCH_HIGHLIGHT_START_SEL", &h.StartSel)
+	e.string("SEARCH_HIGHLIGHT_STOP_SEL", &h.StopSel)
+	e.int("SEARCH_HIGHLIGHT_MAX_WORDS", &h.MaxWords)
+	e.int("SEARCH_HIGHLIGHT_MIN_WORDS", &h.MinWords)
+	e.int("SEARCH_HIGHLIGHT_MAX_FRAGMENTS", &h.MaxFragments)
+	e.string("SEARCH_HIGHLIGHT_FRAGMENT_DELIMITER", &h.FragmentDelimiter)
+	return errors.Join(e.errs...)
+}
+
+// Validate rejects settings the service cannot start with. Settings owned
+// by other packages, such as the parsing mode and the highlight options,
+// are checked where they are applied.
+func (c Config) Validate() error {
+	var errs []error
+	switch c.LogLevel {
+	case "info", "debug":
+	default:
+		errs = append(errs, fmt.Errorf("log_level must be info or debug, got %q", c.LogLevel))
+	}
+	if (c.HTTP.TLSCertFile == "") != (c.HTTP.TLSKeyFile == "") {
+		errs = append(errs, errors.New("http.tls_cert_file and http.tls_key_file must be set together"))
+	}
+	if (c.Admin.TLSCertFile == "") != (c.Admin.TLSKeyFile == "") {
+		errs = append(errs, errors.New("admin.tls_cert_file and admin.tls_key_file must be set together"))
+	}
+	if c.HTTP.Addr == "" && c.Admin.Addr == "" {
+		errs = append(errs, errors.New("http.addr and admin.addr are both empty"))
+	}
+
+	d := c.Database
+	if u, err := url.Parse(d.DSN); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		errs = append(errs, errors.New("database.dsn must be a postgres:// URL"))
+	}
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database pool sizes must not be negative"))
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns (%d) must not exceed max_open_conns (%d)", d.MaxIdleConns, d.MaxOpenConns))
+	}
+	if d.ConnMaxLifetime < 0 || d.StatementTimeout < 0 || d.IdleInTransactionTimeout < 0 || d.SlowQueryThreshold < 0 {
+		errs = append(errs, errors.New("database durations must not be negative"))
+	}
+	if d.StatementBudget < 0 || d.FilterScanRowLimit < 0 {
+		errs = append(errs, errors.New("database.statement_budget and filter_scan_row_limit must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// env reads typed environment variables, collecting parse errors.
+type env struct {
+	errs []error
+}
+
+func (e *env) string(name string, dst *string) {
+	if v, ok := os.LookupEnv(name); ok {
+		*dst = v
+	}
+}
+
+func (e *env) list(name string, dst *[]string) {
+	if v, ok := os.LookupEnv(name); ok {
+		*dst = strings.Split(v, ",")
+	}
+}
+
+func (e *env) bool(name string, dst *bool) {
+	e.parse(name, func(v string) (err error) {
+		switch strings.ToLower(v) {
+		case "1", "true", "yes", "on":
+			*dst = true
+		case "0", "false", "no", "off":
+			*dst = false
+		default:
+			err = fmt.Errorf("not a boolean: %q", v)
+		}
+		return err
+	})
+}
+
+func (e *env) int(name string, dst *int) {
+	e.parse(name, func(v string) (err error) {
+		*dst, err = strconv.Atoi(v)
+		return err
+	})
+}
+
+func (e *env) int64(name string, dst *int64) {
+	e.parse(name, func(v string) (err error) {
+		*dst, err = strconv.ParseInt(v, 10, 64)
+		return err
+	})
+}
+
+func (e *env) float(name string, dst *float64) {
+	e.parse(name, func(v string) (err error) {
+		*dst, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+}
+
+func (e *env) duration(name string, dst *time.Duration) {
+	e.parse(name, func(v string) (err error) {
+		*dst, err = time.ParseDuration(v)
+		return err
+	})
+}
+
+// parse calls set with the trimmed value of name, unless it is unset or
+// blank.
+func (e *env) parse(name string, set func(string) error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	if err := set(v); err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", name, err))
+	}
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrStatementBudgetExceeded is returned for statements issued after a
+// request has used up an enforced statement budget.
+var ErrStatementBudgetExceeded = errors.New("statement budget exceeded")
+
+type budgetKey struct{}
+
+type budget struct {
+	limit   int64
+	enforce bool
+	used    atomic.Int64
+}
+
+// WithStatementBudget returns a context that counts the statements issued
+// with it against limit.
+func WithStatementBudget(ctx context.Context, limit int64, enforce bool) context.Context {
+	return context.WithValue(ctx, budgetKey{}, &budget{limit: limit, enforce: enforce})
+}
+
+// StatementsUsed reports how many statements were issued with ctx since
+// WithStatementBudget was applied to it.
+func StatementsUsed(ctx context.Context) int64 {
+	if b, ok := ctx.Value(budgetKey{}).(*budget); ok {
+		return b.used.Load()
+	}
+	return 0
+}
+
+// spendStatement counts one statement against the budget carried by ctx.
+func spendStatement(ctx context.Context) error {
+	b, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return nil
+	}
+	if b.used.Add(1) > b.limit && b.enforce {
+		return ErrStatementBudgetExceeded
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWithSessionTimeouts(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    string
+	}{
+		{0, ""},
+		{time.Microsecond, "1"},
+		{500 * time.Microsecond, "1"},
+		{time.Millisecond, "1"},
+		{1500 * time.Microsecond, "2"},
+		{30 * time.Second, "30000"},
+	}
+	for _, tt := range tests {
+		dsn, err := withSessionTimeouts("postgres://localhost/db?sslmode=disable", tt.timeout, tt.timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+		for _, param := range []string{"statement_timeout", "idle_in_transaction_session_timeout"} {
+			if got := q.Get(param); got != tt.want {
+				t.Errorf("%s for %s = %q, want %q", param, tt.timeout, got, tt.want)
+			}
+		}
+		if q.Get("sslmode") != "disable" {
+			t.Errorf("dsn %s lost sslmode", dsn)
+		}
+	}
+}
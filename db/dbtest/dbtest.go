@@ -0,0 +1,105 @@
+// Package dbtest gives integration tests a migrated database of their own.
+// Tests using it are skipped unless TEST_DATABASE_URL names a PostgreSQL
+// database they may create schemas in, e.g.
+//
+//	TEST_DATABASE_URL='postgres://postgres@localhost/openapi-go-crud-test?sslmode=disable' go test ./...
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sample/db"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// extensionLock serializes CREATE EXTENSION across test processes, which
+// would otherwise race on the first run against a fresh database.
+const extensionLock = 0x64627465
+
+// Setup points db.DB at a new schema with every numbered migration applied,
+// and drops the schema when t ends.
+func Setup(t testing.TB) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	conn, err := admin.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		fmt.Sprintf("SELECT pg_advisory_lock(%d)", extensionLock),
+		"CREATE EXTENSION IF NOT EXISTS cube SCHEMA public",
+		"CREATE EXTENSION IF NOT EXISTS earthdistance SCHEMA public",
+		fmt.Sprintf("SELECT pg_advisory_unlock(%d)", extensionLock),
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conn.Close()
+
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop test schema: %v", err)
+		}
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema+",public")
+	u.RawQuery = q.Encode()
+	testDB, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { testDB.Close() })
+
+	migrations, err := filepath.Glob(filepath.Join(migrationsDir(), "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testDB.ExecContext(ctx, string(migration)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(path), err)
+		}
+	}
+
+	previous := db.DB
+	db.DB = testDB
+	t.Cleanup(func() { db.DB = previous })
+}
+
+// migrationsDir locates db/migrations from this file, so tests find it
+// whatever package they run in.
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "migrations")
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrConflict is returned when a write would break a uniqueness
+	// constraint.
+	ErrConflict = errors.New("conflicts with an existing item")
+	// ErrInvalidReference is returned when a write refers to a row that
+	// does not exist.
+	ErrInvalidReference = errors.New("refers to a record that does not exist")
+)
+
+// MissingValueError is returned when a write leaves a NOT NULL column
+// empty.
+type MissingValueError struct {
+	Column string
+}
+
+func (e *MissingValueError) Error() string { return e.Column + " is required" }
+
+// BulkError is returned by CreateItems when the database rejects some of
+// the items. Errs lines up with the items: nil entries were acceptable on
+// their own and were not stored only because the insert is all or nothing.
+type BulkError struct {
+	Errs []error
+}
+
+func (e *BulkError) Error() string {
+	var first error
+	rejected := 0
+	for _, err := range e.Errs {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			rejected++
+		}
+	}
+	return fmt.Sprintf("%d of %d items rejected: %v", rejected, len(e.Errs), first)
+}
+
+// NotFound returns the error for a missing row of the named resource, such
+// as "widget not found". It matches ErrNotFound under errors.Is.
+func NotFound(resource string) error {
+	return notFoundError(resource)
+}
+
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) + " not found" }
+
+func (e notFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// Postgres error codes the package translates.
+const (
+	codeNotNullViolation          = "23502"
+	codeForeignKeyViolation       = "23503"
+	codeUniqueViolation           = "23505"
+	codeInvalidTextRepresentation = "22P02"
+)
+
+// Classify translates driver errors into this package's errors, so
+// callers can react to them without knowing Postgres error codes. The
+// original error stays available through errors.As. Other errors are
+// returned unchanged.
+func Classify(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case codeUniqueViolation:
+		return errors.Join(ErrConflict, err)
+	case codeForeignKeyViolation:
+		return errors.Join(ErrInvalidReference, err)
+	case codeNotNullViolation:
+		return errors.Join(&MissingValueError{Column: pqErr.Column}, err)
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{codeUniqueViolation, ErrConflict},
+		{codeForeignKeyViolation, ErrInvalidReference},
+	}
+	for _, tt := range tests {
+		err := Classify(&pq.Error{Code: pq.ErrorCode(tt.code)})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Classify(%s) = %v, want %v", tt.code, err, tt.want)
+		}
+	}
+}
+
+func TestClassifyNotNullViolation(t *testing.T) {
+	driverErr := &pq.Error{Code: codeNotNullViolation, Column: "name"}
+	err := Classify(driverErr)
+	var missing *MissingValueError
+	if !errors.As(err, &missing) || missing.Column != "name" {
+		t.Fatalf("Classify = %v, want a MissingValueError for name", err)
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr != driverErr {
+		t.Errorf("Classify = %v, lost the driver error", err)
+	}
+}
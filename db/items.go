@@ -0,0 +1,367 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sample/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound is returned when no item has the requested id.
+	ErrNotFound = errors.New("item not found")
+	// ErrVersionMismatch is returned by conditional writes when the item
+	// exists but its version is none of the expected ones.
+	ErrVersionMismatch = errors.New("item has been modified")
+)
+
+// itemColumns are the columns an item is read from, in the order
+// itemFields scans them.
+const itemColumns = "id, name, description, latitude, longitude, created_at, updated_at, version"
+
+// itemFields returns the scan destinations for itemColumns.
+func itemFields(item *models.Item) []any {
+	return []any{&item.Id, &item.Name, &item.Description, &item.Latitude, &item.Longitude, &item.CreatedAt, &item.UpdatedAt, &item.Version}
+}
+
+// ItemQuery describes which items ListItems returns. Conditions are ANDed
+// together; Limit and Offset select a page of the result.
+type ItemQuery struct {
+	// OrderBy lists ORDER BY terms such as "name DESC". It is inserted into
+	// the SQL verbatim, so it must only be built from whitelisted columns.
+	// id is always appended as a tiebreaker to keep pages stable.
+	OrderBy []string
+	Limit   int
+	Offset  int
+
+	conditions []string
+	args       []any
+}
+
+// Where adds a SQL condition whose placeholders refer to args. Placeholders
+// are numbered across the whole query, so build conditions with ArgCount.
+func (q *ItemQuery) Where(condition string, args ...any) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// WhereIDs restricts q to the given ids and orders the result the way ids
+// are listed.
+func (q *ItemQuery) WhereIDs(ids []string) {
+	n := q.ArgCount() + 1
+	q.Where(fmt.Sprintf("id = ANY($%d)", n), pq.Array(ids))
+	q.OrderBy = []string{fmt.Sprintf("array_position($%d, id)", n)}
+}
+
+// ArgCount returns how many arguments have been bound so far; the next
+// placeholder is $ArgCount()+1.
+func (q *ItemQuery) ArgCount() int {
+	return len(q.args)
+}
+
+func (q *ItemQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func (q *ItemQuery) sql() (string, []any) {
+	orderBy := append(append([]string(nil), q.OrderBy...), "id")
+	query := "SELECT " + itemColumns + " FROM items" + q.where() + " ORDER BY " + strings.Join(orderBy, ", ")
+	args := append([]any(nil), q.args...)
+	if q.Limit > 0 {
+		args = append(args, q.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if q.Offset > 0 {
+		args = append(args, q.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	return query, args
+}
+
+// ListItems returns the page of items selected by q.
+func ListItems(ctx context.Context, q ItemQuery) ([]models.Item, error) {
+	query, args := q.sql()
+	rows, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Item
+	for rows.Next() {
+		var item models.Item
+		if err := rows.Scan(itemFields(&item)...); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CountItems returns how many items match q, ignoring Limit and Offset.
+func CountItems(ctx context.Context, q ItemQuery) (int64, error) {
+	var total int64
+	err := DB.QueryRowContext(ctx, "SELECT count(*) FROM items"+q.where(), q.args...).Scan(&total)
+	return total, err
+}
+
+// ListItemsScanRows reports how many rows ListItems(ctx, q) would read
+// through full scans, without running it.
+func ListItemsScanRows(ctx context.Context, q ItemQuery) (float64, error) {
+	query, args := q.sql()
+	return FullScanRows(ctx, query, args...)
+}
+
+// GetItem returns the item with the given id.
+func GetItem(ctx context.Context, id string) (models.Item, error) {
+	var item models.Item
+	err := DB.QueryRowContext(ctx, "SELECT "+itemColumns+" FROM items WHERE id = $1", id).
+		Scan(itemFields(&item)...)
+	return item, lookupError(err)
+}
+
+// CreateItem inserts item and sets its generated id and timestamps.
+func CreateItem(ctx context.Context, item *models.Item) error {
+	items := []models.Item{*item}
+	if err := CreateItems(ctx, items); err != nil {
+		return err
+	}
+	*item = items[0]
+	return nil
+}
+
+// CreateItems inserts items with a single multi-row INSERT, so either all
+// of them are stored or none are, and sets their generated ids and
+// timestamps. When the insert fails because of some of the items, the
+// error is a *BulkError saying which.
+func CreateItems(ctx context.Context, items []models.Item) error {
+	ids, err := newItemIDs(ctx, len(items))
+	if err != nil {
+		return err
+	}
+	rows := make([][]any, len(items))
+	for i, item := range items {
+		rows[i] = []any{ids[i], item.Name, item.Description, item.Latitude, item.Longitude}
+	}
+	created, err := insertItems(ctx, ids, rows)
+	if err != nil {
+		if len(items) > 1 {
+			return bulkError(ctx, rows, err)
+		}
+		return err
+	}
+	for i, c := range created {
+		items[i].Id, items[i].CreatedAt, items[i].UpdatedAt, items[i].Version = c.Id, c.CreatedAt, c.UpdatedAt, c.Version
+	}
+	return nil
+}
+
+// insertItems runs the INSERT for rows, whose first value is the item's
+// preallocated id from ids, and returns the stored items in the same
+// order. RETURNING does not promise any row order, so the rows it returns
+// are matched to the items by id.
+func insertItems(ctx context.Context, ids []string, rows [][]any) ([]models.Item, error) {
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	query, args := insertItemsSQL(rows)
+	result, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	created := make([]models.Item, len(rows))
+	n := 0
+	for ; result.Next(); n++ {
+		var item models.Item
+		if err := result.Scan(&item.Id, &item.CreatedAt, &item.UpdatedAt, &item.Version); err != nil {
+			return nil, err
+		}
+		i, ok := index[*item.Id]
+		if !ok {
+			return nil, fmt.Errorf("insert returned unexpected item id %s", *item.Id)
+		}
+		created[i] = item
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	if n != len(rows) {
+		return nil, fmt.Errorf("insert returned %d items, want %d", n, len(rows))
+	}
+	return created, nil
+}
+
+func insertItemsSQL(rows [][]any) (string, []any) {
+	values := make([]string, len(rows))
+	var args []any
+	for i, row := range rows {
+		placeholders := make([]string, len(row))
+		for j, v := range row {
+			args = append(args, v)
+			placeholders[j] = fmt.Sprintf("$%d", len(args))
+		}
+		values[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	return "INSERT INTO items (id, name, description, latitude, longitude) VALUES " + strings.Join(values, ", ") +
+		" RETURNING id, created_at, updated_at, version", args
+}
+
+// bulkError works out which rows made a multi-row insert fail with err by
+// inserting them one at a time, each under a savepoint, in a transaction
+// that is rolled back. It returns a *BulkError when at least one row fails
+// on its own, and err itself when none does or the check cannot be made.
+func bulkError(ctx context.Context, rows [][]any, err error) error {
+	tx, txErr := DB.BeginTx(ctx, nil)
+	if txErr != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	errs := make([]error, len(rows))
+	rejected := false
+	for i, row := range rows {
+		if _, txErr := tx.ExecContext(ctx, "SAVEPOINT bulk_item"); txErr != nil {
+			return err
+		}
+		query, args := insertItemsSQL([][]any{row})
+		if _, rowErr := tx.ExecContext(ctx, query, args...); rowErr != nil {
+			errs[i], rejected = rowErr, true
+			if _, txErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_item"); txErr != nil {
+				return err
+			}
+		}
+	}
+	if !rejected {
+		return err
+	}
+	return &BulkError{Errs: errs}
+}
+
+// The write functions below take the versions the caller expects the item
+// to be at; nil skips the check. When the item exists at another version
+// they fail with ErrVersionMismatch and change nothing.
+
+// UpdateItem replaces the stored fields of the item with the given id and
+// sets item.Id, the timestamps and the new version accordingly.
+func UpdateItem(ctx context.Context, id string, versions []int64, item *models.Item) error {
+	err := DB.QueryRowContext(ctx, "UPDATE items SET name = $1, description = $2, latitude = $3, longitude = $4, updated_at = now(), version = version + 1 WHERE id = $5"+versionCondition(versions, 6)+" RETURNING id, created_at, updated_at, version",
+		versionArgs([]any{item.Name, item.Description, item.Latitude, item.Longitude, id}, versions)...).
+		Scan(&item.Id, &item.CreatedAt, &item.UpdatedAt, &item.Version)
+	return writeError(ctx, id, versions, err)
+}
+
+// PatchItem updates only the columns set in patch on the item with the
+// given id and returns the stored result. A patch that sets nothing leaves
+// the row untouched. The read-only fields of patch are ignored; callers
+// reject them beforehand.
+func PatchItem(ctx context.Context, id string, versions []int64, patch models.ItemPatch) (models.Item, error) {
+	var sets []string
+	var args []any
+	for _, field := range []struct {
+		column string
+		set    bool
+		value  any
+	}{
+		{"name", patch.Name.Set, patch.Name.Value},
+		{"description", patch.Description.Set, patch.Description.Value},
+		{"latitude", patch.Latitude.Set, patch.Latitude.Value},
+		{"longitude", patch.Longitude.Set, patch.Longitude.Value},
+	} {
+		if !field.set {
+			continue
+		}
+		args = append(args, field.value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", field.column, len(args)))
+	}
+	if len(sets) == 0 {
+		item, err := GetItem(ctx, id)
+		if err == nil && versions != nil && !slices.Contains(versions, *item.Version) {
+			err = ErrVersionMismatch
+		}
+		return item, err
+	}
+
+	sets = append(sets, "updated_at = now()", "version = version + 1")
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE items SET %s WHERE id = $%d%s RETURNING %s", strings.Join(sets, ", "), len(args), versionCondition(versions, len(args)+1), itemColumns)
+	var item models.Item
+	err := DB.QueryRowContext(ctx, query, versionArgs(args, versions)...).Scan(itemFields(&item)...)
+	return item, writeError(ctx, id, versions, err)
+}
+
+// DeleteItem removes the item with the given id.
+func DeleteItem(ctx context.Context, id string, versions []int64) error {
+	result, err := DB.ExecContext(ctx, "DELETE FROM items WHERE id = $1"+versionCondition(versions, 2), versionArgs([]any{id}, versions)...)
+	if err != nil {
+		return lookupError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return writeError(ctx, id, versions, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// versionCondition returns the WHERE clause suffix restricting a write to
+// versions, bound as placeholder n, or "" when versions is nil.
+func versionCondition(versions []int64, n int) string {
+	if versions == nil {
+		return ""
+	}
+	return fmt.Sprintf(" AND version = ANY($%d)", n)
+}
+
+// versionArgs appends the argument for versionCondition to args.
+func versionArgs(args []any, versions []int64) []any {
+	if versions == nil {
+		return args
+	}
+	return append(args, pq.Array(versions))
+}
+
+// writeError is lookupError for conditional writes: when no row matched,
+// it tells a missing item from one at another version.
+func writeError(ctx context.Context, id string, versions []int64, err error) error {
+	err = lookupError(err)
+	if err != ErrNotFound || versions == nil {
+		return err
+	}
+	var exists bool
+	if err := DB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM items WHERE id = $1)", id).Scan(&exists); err != nil {
+		return lookupError(err)
+	}
+	if exists {
+		return ErrVersionMismatch
+	}
+	return ErrNotFound
+}
+
+// lookupError translates the errors of a statement addressing an item by
+// id: no matching row, or an id Postgres cannot even parse for the column
+// type, both mean the item does not exist. Other errors go through
+// Classify.
+func lookupError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == codeInvalidTextRepresentation {
+		return ErrNotFound
+	}
+	return Classify(err)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// UUIDKeys makes CreateItem and CreateItems assign random (version 4) UUID
+// ids instead of taking the next value of the id sequence. The items table
+// must have been converted with migrations/optional/uuid_primary_keys.sql.
+var UUIDKeys bool
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValidID reports whether id is well-formed for the configured key type.
+func ValidID(id string) bool {
+	if UUIDKeys {
+		return uuidPattern.MatchString(id)
+	}
+	_, err := strconv.ParseInt(id, 10, 32)
+	return err == nil
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// newItemIDs allocates the ids of n new items up front, random UUIDs or
+// values of the id sequence, so the rows an INSERT returns can be matched
+// to its items by key.
+func newItemIDs(ctx context.Context, n int) ([]string, error) {
+	if UUIDKeys {
+		ids := make([]string, n)
+		for i := range ids {
+			id, err := newUUID()
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = id
+		}
+		return ids, nil
+	}
+
+	rows, err := DB.QueryContext(ctx, "SELECT nextval(pg_get_serial_sequence('items', 'id')) FROM generate_series(1, $1)", n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, n)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) != n {
+		return nil, fmt.Errorf("allocated %d item ids, want %d", len(ids), n)
+	}
+	return ids, nil
+}
+
+// checkKeyType fails when UUIDKeys does not match the type of items.id, so
+// a misconfigured deployment stops at startup rather than on the first
+// insert. A missing table is left for the migrations to create.
+func checkKeyType(ctx context.Context) error {
+	var dataType string
+	err := DB.QueryRowContext(ctx, `SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'items' AND column_name = 'id'`).Scan(&dataType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if UUIDKeys != (dataType == "uuid") {
+		return fmt.Errorf("items.id is of type %s", dataType)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+-- Migrations in this directory are applied in filename order, e.g.
+--   for f in db/migrations/*.sql; do psql "$DATABASE_URL" -f "$f"; done
+-- and are written to be safe to re-run.
+CREATE TABLE IF NOT EXISTS items (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT
+);
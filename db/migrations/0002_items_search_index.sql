@@ -0,0 +1,4 @@
+-- Full-text index backing GET /items/search. The expression must match
+-- searchDocument in db/search.go for the planner to use it.
+CREATE INDEX IF NOT EXISTS items_search_idx ON items
+    USING GIN (to_tsvector('english', coalesce(name, '') || ' ' || coalesce(description, '')));
@@ -0,0 +1,11 @@
+-- Optional coordinates backing GET /items/nearby. The index expression
+-- must match itemPoint in db/nearby.go.
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS items_location_idx ON items
+    USING GIST (ll_to_earth(latitude, longitude));
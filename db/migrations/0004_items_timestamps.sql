@@ -0,0 +1,8 @@
+-- Creation and last-update times of items. Existing rows get the time the
+-- migration runs.
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Backs the created_after and created_before filters of GET /items.
+CREATE INDEX IF NOT EXISTS items_created_at_idx ON items (created_at);
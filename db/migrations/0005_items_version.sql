@@ -0,0 +1,3 @@
+-- Optimistic concurrency: version is bumped on every write and exposed as
+-- the item's ETag.
+ALTER TABLE items ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
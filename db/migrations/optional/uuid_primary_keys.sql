@@ -0,0 +1,8 @@
+-- Switches items to UUID ids for deployments running with
+-- database.uuid_primary_keys (DB_UUID_PRIMARY_KEYS=true). Apply it once,
+-- after the numbered migrations, to a new database: existing items get new
+-- random ids, which breaks any reference clients kept to the old ones.
+ALTER TABLE items ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE items ALTER COLUMN id TYPE UUID USING gen_random_uuid();
+ALTER TABLE items ALTER COLUMN id SET DEFAULT gen_random_uuid();
+DROP SEQUENCE IF EXISTS items_id_seq;
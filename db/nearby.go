@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+
+	"sample/models"
+)
+
+// itemPoint is the earthdistance point of an item; it must match the
+// expression of items_location_idx for the index to be used.
+const itemPoint = "ll_to_earth(latitude, longitude)"
+
+// NearbyHit is an item within the radius passed to NearbyItems.
+type NearbyHit struct {
+	Item     models.Item
+	Distance float64 // metres
+}
+
+// NearbyItems returns up to limit items within radius metres of lat/lon,
+// nearest first. It needs the cube and earthdistance extensions created by
+// migration 0003; items without coordinates never match.
+func NearbyItems(ctx context.Context, lat, lon, radius float64, limit int) ([]NearbyHit, error) {
+	// earth_box is a bounding cube that can use the GiST index; the
+	// earth_distance check then trims its corners.
+	rows, err := DB.QueryContext(ctx, `SELECT `+itemColumns+`, earth_distance(`+itemPoint+`, p) AS distance
+	FROM items, ll_to_earth($1, $2) AS p
+	WHERE latitude IS NOT NULL AND longitude IS NOT NULL
+		AND earth_box(p, $3) @> `+itemPoint+`
+		AND earth_distance(`+itemPoint+`, p) <= $3
+	ORDER BY distance, id
+	LIMIT $4`, lat, lon, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []NearbyHit
+	for rows.Next() {
+		var hit NearbyHit
+		if err := rows.Scan(append(itemFields(&hit.Item), &hit.Distance)...); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ScanRowLimit is the number of rows a user-filtered query may plan to read
+// through full scans before it is rejected. Zero disables the check.
+// Connect sets it from the configuration.
+var ScanRowLimit float64
+
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexCond    string     `json:"Index Cond"`
+	TIDCond      string     `json:"TID Cond"`
+	PlanRows     float64    `json:"Plan Rows"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// FullScanRows asks the planner how many rows query would read through
+// full scans, without executing it.
+func FullScanRows(ctx context.Context, query string, args ...any) (float64, error) {
+	var raw []byte
+	if err := DB.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&raw); err != nil {
+		return 0, err
+	}
+	return planFullScanRows(raw, func(relation string) (float64, error) {
+		return relationRows(ctx, relation)
+	})
+}
+
+// planFullScanRows sums the rows read by the full scans in an EXPLAIN
+// (FORMAT JSON) plan. A full scan is any scan node without an index
+// condition: sequential scans, parallel or not, and index scans that walk
+// a whole index (the primary key, to satisfy ORDER BY id) while filtering.
+// Each reads its whole relation whatever its filter or an enclosing LIMIT
+// says, so it counts as the relation's size, looked up with tableRows.
+// Plan Rows would only be the rows the node returns, and per worker for
+// parallel scans.
+func planFullScanRows(raw []byte, tableRows func(relation string) (float64, error)) (float64, error) {
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return 0, err
+	}
+	sizes := map[string]float64{}
+	var rows float64
+	var walk func(n planNode) error
+	walk = func(n planNode) error {
+		if isFullScan(n) {
+			size, ok := sizes[n.RelationName]
+			if !ok {
+				var err error
+				if size, err = tableRows(n.RelationName); err != nil {
+					return err
+				}
+				sizes[n.RelationName] = size
+			}
+			rows += size
+		}
+		for _, child := range n.Plans {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, p := range plans {
+		if err := walk(p.Plan); err != nil {
+			return 0, err
+		}
+	}
+	return rows, nil
+}
+
+func isFullScan(n planNode) bool {
+	switch {
+	case n.RelationName == "" || !strings.HasSuffix(n.NodeType, "Scan"):
+		return false
+	case n.NodeType == "Bitmap Heap Scan":
+		// Bounded by the Bitmap Index Scans below it.
+		return false
+	}
+	return n.IndexCond == "" && n.TIDCond == ""
+}
+
+// relationRows returns the planner's estimate of the rows in relation,
+// which also covers tables that have not been analyzed yet.
+func relationRows(ctx context.Context, relation string) (float64, error) {
+	var raw []byte
+	if err := DB.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) SELECT 1 FROM "+pq.QuoteIdentifier(relation)).Scan(&raw); err != nil {
+		return 0, err
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil || len(plans) == 0 {
+		return 0, err
+	}
+	return plans[0].Plan.PlanRows, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import "testing"
+
+func TestPlanFullScanRows(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+		want float64
+	}{
+		{
+			name: "seq scan counts the table, not its output",
+			plan: `[{"Plan": {"Node Type": "Limit", "Plan Rows": 50, "Plans": [
+				{"Node Type": "Seq Scan", "Relation Name": "items", "Plan Rows": 3, "Filter": "(name = 'x'::text)"}]}}]`,
+			want: 1e6,
+		},
+		{
+			name: "parallel seq scan under gather",
+			plan: `[{"Plan": {"Node Type": "Gather", "Plan Rows": 10, "Plans": [
+				{"Node Type": "Parallel Seq Scan", "Relation Name": "items", "Plan Rows": 4}]}}]`,
+			want: 1e6,
+		},
+		{
+			name: "pkey index scan with only a filter walks the whole index",
+			plan: `[{"Plan": {"Node Type": "Limit", "Plan Rows": 50, "Plans": [
+				{"Node Type": "Index Scan", "Index Name": "items_pkey", "Relation Name": "items", "Plan Rows": 50,
+				 "Filter": "(description = 'x'::text)"}]}}]`,
+			want: 1e6,
+		},
+		{
+			name: "index only scan without condition",
+			plan: `[{"Plan": {"Node Type": "Index Only Scan", "Index Name": "items_pkey", "Relation Name": "items", "Plan Rows": 1e6}}]`,
+			want: 1e6,
+		},
+		{
+			name: "index scan with a condition is narrowed",
+			plan: `[{"Plan": {"Node Type": "Index Scan", "Index Name": "items_pkey", "Relation Name": "items", "Plan Rows": 1,
+				"Index Cond": "(id = 7)", "Filter": "(name = 'x'::text)"}}]`,
+			want: 0,
+		},
+		{
+			name: "bitmap heap scan is bounded by its index scan",
+			plan: `[{"Plan": {"Node Type": "Bitmap Heap Scan", "Relation Name": "items", "Plan Rows": 20,
+				"Recheck Cond": "(created_at > now())", "Plans": [
+				{"Node Type": "Bitmap Index Scan", "Index Name": "items_created_at_idx", "Plan Rows": 20,
+				 "Index Cond": "(created_at > now())"}]}}]`,
+			want: 0,
+		},
+		{
+			name: "each full scan counts",
+			plan: `[{"Plan": {"Node Type": "Append", "Plans": [
+				{"Node Type": "Seq Scan", "Relation Name": "items", "Plan Rows": 1},
+				{"Node Type": "Seq Scan", "Relation Name": "items", "Plan Rows": 1}]}}]`,
+			want: 2e6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups := 0
+			got, err := planFullScanRows([]byte(tt.plan), func(relation string) (float64, error) {
+				lookups++
+				if relation != "items" {
+					t.Errorf("size lookup for %q", relation)
+				}
+				return 1e6, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("planFullScanRows = %g, want %g", got, tt.want)
+			}
+			if lookups > 1 {
+				t.Errorf("relation size looked up %d times, want at most once", lookups)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sample/filter"
+	"sample/models"
+)
+
+// searchDocument is the text search vector indexed by items_search_idx
+// (db/migrations/0002_items_search_index.sql); keep the two identical.
+const searchDocument = `to_tsvector('english', coalesce(name, '') || ' ' || coalesce(description, ''))`
+
+// HighlightOptions controls the headline excerpts returned by SearchItems;
+// the fields map onto ts_headline's options of the same name. Set them with
+// SetHighlightOptions.
+var HighlightOptions = Highlight{
+	StartSel:     "<b>",
+	StopSel:      "</b>",
+	MaxWords:     35,
+	MinWords:     15,
+	MaxFragments: 0,
+}
+
+// Highlight holds ts_headline options. MaxFragments > 0 switches from one
+// excerpt to up to that many fragments joined by FragmentDelimiter.
+type Highlight struct {
+	StartSel          string
+	StopSel           string
+	MaxWords          int
+	MinWords          int
+	MaxFragments      int
+	FragmentDelimiter string
+}
+
+// String renders h in ts_headline's option syntax.
+func (h Highlight) String() string {
+	opts := fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=%d, MinWords=%d, MaxFragments=%d`,
+		h.StartSel, h.StopSel, h.MaxWords, h.MinWords, h.MaxFragments)
+	if h.FragmentDelimiter != "" {
+		opts += fmt.Sprintf(`, FragmentDelimiter="%s"`, h.FragmentDelimiter)
+	}
+	return opts
+}
+
+// SetHighlightOptions validates h and makes it the HighlightOptions.
+func SetHighlightOptions(h Highlight) error {
+	if err := h.validate(); err != nil {
+		return err
+	}
+	HighlightOptions = h
+	return nil
+}
+
+// validate rejects options ts_headline would refuse or misparse.
+func (h Highlight) validate() error {
+	for _, v := range []string{h.StartSel, h.StopSel, h.FragmentDelimiter} {
+		if strings.Contains(v, `"`) {
+			return fmt.Errorf("highlight markers must not contain double quotes: %q", v)
+		}
+	}
+	if h.MinWords < 1 || h.MaxWords <= h.MinWords {
+		return fmt.Errorf("highlight MinWords must be at least 1 and below MaxWords (got %d and %d)", h.MinWords, h.MaxWords)
+	}
+	if h.MaxFragments < 0 {
+		return fmt.Errorf("highlight MaxFragments must not be negative (got %d)", h.MaxFragments)
+	}
+	return nil
+}
+
+// SearchHit is an item matched by SearchItems.
+type SearchHit struct {
+	Item models.Item
+	// Rank orders hits by relevance; it is zero when the search fell back
+	// to substring matching.
+	Rank float32
+	// Headline is the matched text with the query terms highlighted.
+	Headline string
+}
+
+// SearchItems runs a full-text search over item names and descriptions,
+// best matches first. Without the search index it degrades to a
+// case-insensitive substring match so the endpoint keeps working, just
+// slower and unranked.
+func SearchItems(ctx context.Context, text string, limit int) ([]SearchHit, error) {
+	indexed, err := searchIndexExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + itemColumns + `,
+		ts_rank(` + searchDocument + `, q) AS rank,
+		ts_headline('english', coalesce(name, '') || ' ' || coalesce(description, ''), q, $3)
+	FROM items, websearch_to_tsquery('english', $1) AS q
+	WHERE ` + searchDocument + ` @@ q
+	ORDER BY rank DESC, id
+	LIMIT $2`
+	args := []any{text, limit, HighlightOptions.String()}
+	if !indexed {
+		query = `SELECT ` + itemColumns + `, 0, coalesce(name, '') || ' ' || coalesce(description, '')
+		FROM items
+		WHERE name ILIKE $1 OR description ILIKE $1
+		ORDER BY id
+		LIMIT $2`
+		args = []any{"%" + filter.EscapeLike(text) + "%", limit}
+	}
+
+	rows, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(append(itemFields(&hit.Item), &hit.Rank, &hit.Headline)...); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+func searchIndexExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := DB.QueryRowContext(ctx, "SELECT to_regclass('items_search_idx') IS NOT NULL").Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,309 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statementLogger decides which statements are logged and how their bound
+// values are rendered.
+type statementLogger struct {
+	// all logs every statement; otherwise only slow ones are reported.
+	all bool
+	// slow is the duration past which a statement is logged as slow. Zero
+	// disables slow statement reporting.
+	slow time.Duration
+	// explain captures the query plan of slow statements in the background.
+	explain bool
+	// plans holds a token for every plan being captured. When it is full,
+	// slow statements are logged without a plan, so a burst of them cannot
+	// pile EXPLAINs onto a database that is already struggling.
+	plans chan struct{}
+	// redact holds the lower-cased names of columns whose bound values must
+	// never reach the logs.
+	redact map[string]bool
+}
+
+func newStatementLogger(redactColumns []string) *statementLogger {
+	l := &statementLogger{redact: map[string]bool{}, plans: make(chan struct{}, maxConcurrentPlans)}
+	for _, col := range redactColumns {
+		if col = strings.TrimSpace(col); col != "" {
+			l.redact[strings.ToLower(col)] = true
+		}
+	}
+	return l
+}
+
+func (l *statementLogger) log(query string, args []driver.NamedValue, rows int64, elapsed time.Duration, err error) {
+	slow := l.slow > 0 && elapsed >= l.slow
+	if !l.all && !slow {
+		return
+	}
+	prefix := "sql"
+	if slow {
+		prefix = "slow sql"
+	}
+	flat := strings.Join(strings.Fields(query), " ")
+	if err != nil {
+		log.Printf("%s: %s args=%s duration=%s error=%v", prefix, flat, l.formatArgs(flat, args), elapsed, err)
+	} else {
+		log.Printf("%s: %s args=%s rows=%d duration=%s", prefix, flat, l.formatArgs(flat, args), rows, elapsed)
+	}
+	if slow && l.explain && !isExplain(query) {
+		select {
+		case l.plans <- struct{}{}:
+			go func() {
+				defer func() { <-l.plans }()
+				l.logPlan(flat, args)
+			}()
+		default:
+			log.Printf("slow sql: plan skipped for %s: %d plans already being captured", flat, maxConcurrentPlans)
+		}
+	}
+}
+
+const (
+	// explainTimeout bounds the background EXPLAIN issued for a slow statement.
+	explainTimeout = 5 * time.Second
+	// maxConcurrentPlans is how many of those EXPLAINs may run at once.
+	maxConcurrentPlans = 2
+)
+
+// logPlan runs EXPLAIN (without ANALYZE, so the statement is not executed
+// again) for a slow statement and logs the resulting plan.
+func (l *statementLogger) logPlan(query string, args []driver.NamedValue) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	rows, err := DB.QueryContext(ctx, "EXPLAIN "+query, values...)
+	if err != nil {
+		log.Printf("slow sql: explain failed for %s: %v", query, err)
+		return
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			log.Printf("slow sql: explain failed for %s: %v", query, err)
+			return
+		}
+		plan = append(plan, line)
+	}
+	log.Printf("slow sql: plan for %s\n%s", query, strings.Join(plan, "\n"))
+}
+
+func isExplain(query string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "EXPLAIN")
+}
+
+// formatArgs renders the bound values of query for the log. Once any
+// column is to be redacted it fails closed: a value is only shown when
+// every column it can be tied to is known and not redacted, so values the
+// parser cannot place, such as a search string passed to a function, are
+// hidden too.
+func (l *statementLogger) formatArgs(query string, args []driver.NamedValue) string {
+	if len(args) == 0 {
+		return "[]"
+	}
+	columns := placeholderColumns(query)
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if l.redacts(columns[arg.Ordinal]) {
+			parts[i] = "[REDACTED]"
+			continue
+		}
+		parts[i] = fmt.Sprintf("%v", arg.Value)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func (l *statementLogger) redacts(columns []string) bool {
+	if len(l.redact) == 0 {
+		return false
+	}
+	if len(columns) == 0 {
+		return true
+	}
+	for _, col := range columns {
+		if l.redact[col] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	comparisonPlaceholder = regexp.MustCompile(`(?i)(\w+)\s*(?:=|<>|!=|<=|>=|<|>|\b(?:NOT\s+)?I?LIKE\b)\s*(?:(?:ANY|ALL)\s*\(\s*)?\$(\d+)`)
+	listPlaceholders      = regexp.MustCompile(`(?i)(\w+)\s+(?:NOT\s+)?IN\s*\(([^)]*)\)`)
+	pagingPlaceholder     = regexp.MustCompile(`(?i)\b(LIMIT|OFFSET)\s+\$(\d+)`)
+	insertPlaceholders    = regexp.MustCompile(`(?is)INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES\s*(.*?)(?:\bRETURNING\b|\bON\s+CONFLICT\b|$)`)
+	placeholder           = regexp.MustCompile(`\$(\d+)`)
+)
+
+// placeholderColumns maps placeholder ordinals ($1 => 1) to the lower-cased
+// columns they are bound against, as far as that can be told from the SQL
+// text: comparisons and assignments ("col = $n", "col NOT ILIKE $n",
+// "col = ANY($n)"), IN and NOT IN lists, INSERT column lists, and LIMIT and
+// OFFSET, which count as columns of those names. A placeholder used in
+// several places lists every column; one the parser cannot place has none.
+func placeholderColumns(query string) map[int][]string {
+	columns := map[int][]string{}
+	add := func(ordinal, column string) {
+		if n, err := strconv.Atoi(ordinal); err == nil {
+			columns[n] = append(columns[n], strings.ToLower(strings.TrimSpace(column)))
+		}
+	}
+	for _, m := range comparisonPlaceholder.FindAllStringSubmatch(query, -1) {
+		add(m[2], m[1])
+	}
+	for _, m := range listPlaceholders.FindAllStringSubmatch(query, -1) {
+		for _, p := range placeholder.FindAllStringSubmatch(m[2], -1) {
+			add(p[1], m[1])
+		}
+	}
+	for _, m := range pagingPlaceholder.FindAllStringSubmatch(query, -1) {
+		add(m[2], m[1])
+	}
+	if m := insertPlaceholders.FindStringSubmatch(query); m != nil {
+		names := strings.Split(m[1], ",")
+		for i, p := range placeholder.FindAllStringSubmatch(m[2], -1) {
+			add(p[1], names[i%len(names)])
+		}
+	}
+	return columns
+}
+
+// instrumentedConnector wraps the driver connector so every connection
+// handed to the pool counts its statements against the request budget and
+// reports them to logger.
+type instrumentedConnector struct {
+	driver.Connector
+	logger *statementLogger
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, logger: c.logger}, nil
+}
+
+// instrumentedConn forwards to the underlying driver connection, counting
+// and timing the statements that go through the context-aware query and
+// exec paths.
+type instrumentedConn struct {
+	driver.Conn
+	logger *statementLogger
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := spendStatement(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		c.logger.log(query, args, 0, time.Since(start), err)
+		return nil, err
+	}
+	return &loggingRows{Rows: rows, logger: c.logger, query: query, args: args, start: start}, nil
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := spendStatement(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	var affected int64
+	if err == nil {
+		affected, _ = result.RowsAffected()
+	}
+	c.logger.log(query, args, affected, time.Since(start), err)
+	return result, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// loggingRows counts the rows read by the caller and logs the statement once
+// the result set is closed, so the log line carries the real row count.
+type loggingRows struct {
+	driver.Rows
+	logger *statementLogger
+	query  string
+	args   []driver.NamedValue
+	start  time.Time
+	rows   int64
+	err    error
+}
+
+func (r *loggingRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.rows++
+	} else if err != io.EOF {
+		r.err = err
+	}
+	return err
+}
+
+func (r *loggingRows) Close() error {
+	err := r.Rows.Close()
+	r.logger.log(r.query, r.args, r.rows, time.Since(r.start), r.err)
+	return err
+}
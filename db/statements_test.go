@@ -0,0 +1,108 @@
+package db
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"log"
+	"os"
+	"sample/filter"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowStatementPlanIsSkippedWhenGateIsFull(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	l := newStatementLogger(nil)
+	l.slow = time.Millisecond
+	l.explain = true
+	for range maxConcurrentPlans {
+		l.plans <- struct{}{}
+	}
+
+	// With the gate full no EXPLAIN may be started; DB is nil here, so one
+	// that was would panic.
+	l.log("SELECT * FROM items WHERE name = $1", nil, 0, time.Second, nil)
+	if !strings.Contains(out.String(), "plan skipped") {
+		t.Fatalf("log output = %q, want the plan reported as skipped", out.String())
+	}
+}
+
+func TestFormatArgsRedaction(t *testing.T) {
+	l := newStatementLogger([]string{"name", "Description"})
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+		want  string
+	}{
+		{"comparison", "SELECT * FROM items WHERE name = $1 AND id > $2", []any{"secret", 4}, "[[REDACTED], 4]"},
+		{"assignment", "UPDATE items SET name = $1, latitude = $2 WHERE id = $3", []any{"secret", 1.5, 7}, "[[REDACTED], 1.5, 7]"},
+		{"insert", "INSERT INTO items (id, name, description) VALUES ($1, $2, $3), ($4, $5, $6) RETURNING id", []any{1, "a", "b", 2, "c", "d"},
+			"[1, [REDACTED], [REDACTED], 2, [REDACTED], [REDACTED]]"},
+		{"in list", "SELECT * FROM items WHERE name IN ($1, $2) AND id IN ($3)", []any{"s1", "s2", 3}, "[[REDACTED], [REDACTED], 3]"},
+		{"not in list", "SELECT * FROM items WHERE name NOT IN ($1,$2)", []any{"s1", "s2"}, "[[REDACTED], [REDACTED]]"},
+		{"not like", "SELECT * FROM items WHERE name NOT LIKE $1", []any{"s%"}, "[[REDACTED]]"},
+		{"not ilike", "SELECT * FROM items WHERE description NOT ILIKE $1", []any{"%s%"}, "[[REDACTED]]"},
+		{"ilike on two columns", "SELECT * FROM items WHERE id = $2 OR name ILIKE $1 OR latitude ILIKE $1", []any{"%s%", 1}, "[[REDACTED], 1]"},
+		{"any", "SELECT * FROM items WHERE name = ANY($1) AND version = ANY($2)", []any{"{a,b}", "{1}"}, "[[REDACTED], {1}]"},
+		{"function argument", "SELECT * FROM items, websearch_to_tsquery('english', $1) AS q", []any{"secret"}, "[[REDACTED]]"},
+		{"limit and offset", "SELECT * FROM items ORDER BY id LIMIT $1 OFFSET $2", []any{50, 100}, "[50, 100]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]driver.NamedValue, len(tt.args))
+			for i, v := range tt.args {
+				args[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+			}
+			if got := l.formatArgs(tt.query, args); got != tt.want {
+				t.Errorf("formatArgs = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatArgsWithoutRedaction(t *testing.T) {
+	l := newStatementLogger(nil)
+	args := []driver.NamedValue{{Ordinal: 1, Value: "visible"}}
+	if got := l.formatArgs("SELECT websearch_to_tsquery('english', $1)", args); got != "[visible]" {
+		t.Errorf("formatArgs = %s, want [visible]", got)
+	}
+}
+
+func TestFormatArgsRedactsCompiledFilters(t *testing.T) {
+	fields := map[string]filter.Field{
+		"id":   {Column: "id", Operators: []string{filter.Equal, filter.Greater, filter.In}},
+		"name": {Column: "name", Text: true, Operators: []string{filter.Equal, filter.NotEqual, filter.In, filter.Out}},
+	}
+	l := newStatementLogger([]string{"name"})
+	for _, expr := range []string{
+		"name==secret",
+		"name!=secret*",
+		"name==*secret*;id=gt=1",
+		"name=in=(secret,secret2)",
+		"name=out=(secret,secret2),id=in=(1,2)",
+	} {
+		node, err := filter.Parse(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		where, args, err := filter.Compile(node, fields, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := ItemQuery{Limit: 50}
+		q.Where(where, args...)
+		query, values := q.sql()
+		named := make([]driver.NamedValue, len(values))
+		for i, v := range values {
+			named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+		}
+		if got := l.formatArgs(query, named); strings.Contains(got, "secret") {
+			t.Errorf("filter %q: %s logged args %s", expr, query, got)
+		}
+	}
+}
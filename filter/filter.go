@@ -0,0 +1,393 @@
+// Package filter parses RSQL/FIQL filter expressions such as
+// `name==foo*;id=gt=10` and compiles them into parameterized SQL.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is an element of a parsed filter expression.
+type Node interface {
+	node()
+}
+
+// And matches when every operand matches (`;` in the expression).
+type And []Node
+
+// Or matches when any operand matches (`,` in the expression).
+type Or []Node
+
+// Comparison compares a field against one or more values.
+type Comparison struct {
+	Field    string
+	Operator string
+	Values   []string
+}
+
+func (And) node()        {}
+func (Or) node()         {}
+func (Comparison) node() {}
+
+// Operators understood by the parser. The symbolic RSQL aliases (<, <=, >,
+// >=) are normalized to their FIQL spelling.
+const (
+	Equal          = "=="
+	NotEqual       = "!="
+	Less           = "=lt="
+	LessOrEqual    = "=le="
+	Greater        = "=gt="
+	GreaterOrEqual = "=ge="
+	In             = "=in="
+	Out            = "=out="
+)
+
+var aliases = map[string]string{
+	"<":  Less,
+	"<=": LessOrEqual,
+	">":  Greater,
+	">=": GreaterOrEqual,
+}
+
+// MaxDepth is how deeply parenthesized groups may nest. It bounds the
+// recursion of the parser and the size of the SQL a filter compiles to.
+const MaxDepth = 32
+
+// Parse parses expr into an AST.
+func Parse(expr string) (Node, error) {
+	p := &parser{input: expr}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+	return node, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+	depth int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("filter: position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) parseOr() (Node, error) {
+	var operands Or
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	var operands And
+	for {
+		node, err := p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+		if p.peek() != ';' {
+			break
+		}
+		p.pos++
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+func (p *parser) parseConstraint() (Node, error) {
+	if p.peek() != '(' {
+		return p.parseComparison()
+	}
+	if p.depth == MaxDepth {
+		return nil, p.errorf("groups nested more than %d deep", MaxDepth)
+	}
+	p.pos++
+	p.depth++
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("missing closing parenthesis")
+	}
+	p.pos++
+	p.depth--
+	return node, nil
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	start := p.pos
+	for p.pos < len(p.input) && isSelectorChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected field name")
+	}
+	field := p.input[start:p.pos]
+
+	operator, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	list := p.peek() == '('
+	if list {
+		p.pos++
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+	} else {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = []string{value}
+	}
+
+	if multi := operator == In || operator == Out; multi && !list {
+		return nil, fmt.Errorf("filter: %s%s expects a parenthesized list", field, operator)
+	} else if !multi && list {
+		return nil, fmt.Errorf("filter: %s%s expects a single value", field, operator)
+	}
+	return Comparison{Field: field, Operator: operator, Values: values}, nil
+}
+
+func (p *parser) parseOperator() (string, error) {
+	rest := p.input[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			if alias, ok := aliases[op]; ok {
+				return alias, nil
+			}
+			return op, nil
+		}
+	}
+	if strings.HasPrefix(rest, "=") {
+		end := strings.IndexByte(rest[1:], '=')
+		if end > 0 {
+			op := rest[:end+2]
+			switch op {
+			case Less, LessOrEqual, Greater, GreaterOrEqual, In, Out:
+				p.pos += len(op)
+				return op, nil
+			}
+			return "", p.errorf("unknown operator %q", op)
+		}
+	}
+	return "", p.errorf("expected operator")
+}
+
+func (p *parser) parseValue() (string, error) {
+	if q := p.peek(); q == '"' || q == '\'' {
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.input); p.pos++ {
+			c := p.input[p.pos]
+			switch {
+			case c == '\\' && p.pos+1 < len(p.input):
+				p.pos++
+				b.WriteByte(p.input[p.pos])
+			case c == q:
+				p.pos++
+				return b.String(), nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", p.errorf("unterminated string")
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !isReserved(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected value")
+	}
+	return p.input[start:p.pos], nil
+}
+
+func isSelectorChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isReserved(c byte) bool {
+	return strings.IndexByte("\"'();,=!~<> ", c) >= 0
+}
+
+// Field describes a filterable field: the column it maps to and the
+// operators allowed on it. Wildcard matching (`*`) with == and != is only
+// accepted on Text fields. Indexed marks fields backed by an index, which
+// are the ones to suggest when a filter is too expensive. Valid, if set,
+// rejects values the column cannot hold, so they fail as a bad filter
+// rather than as a database error.
+type Field struct {
+	Column    string
+	Operators []string
+	Text      bool
+	Indexed   bool
+	Valid     func(value string) bool
+}
+
+func (f Field) allows(operator string) bool {
+	for _, op := range f.Operators {
+		if op == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// Compile turns node into a SQL boolean expression using only the columns
+// and operators whitelisted in fields. Values are returned as arguments for
+// placeholders numbered from offset+1. Every value is bound in the form
+// "column op $n", "column [NOT] LIKE $n" or "column [NOT] IN (...)", which
+// the db package's statement log relies on to redact it.
+func Compile(node Node, fields map[string]Field, offset int) (string, []any, error) {
+	c := &compiler{fields: fields, offset: offset}
+	sql, err := c.compile(node)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, c.args, nil
+}
+
+type compiler struct {
+	fields map[string]Field
+	offset int
+	args   []any
+}
+
+func (c *compiler) placeholder(value any) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(c.offset+len(c.args))
+}
+
+func (c *compiler) compile(node Node) (string, error) {
+	switch n := node.(type) {
+	case And:
+		return c.join(n, " AND ")
+	case Or:
+		return c.join(n, " OR ")
+	case Comparison:
+		return c.comparison(n)
+	}
+	return "", fmt.Errorf("filter: unsupported node %T", node)
+}
+
+func (c *compiler) join(nodes []Node, sep string) (string, error) {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		sql, err := c.compile(n)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = sql
+	}
+	return "(" + strings.Join(parts, sep) + ")", nil
+}
+
+var sqlOperators = map[string]string{
+	Equal:          "=",
+	NotEqual:       "<>",
+	Less:           "<",
+	LessOrEqual:    "<=",
+	Greater:        ">",
+	GreaterOrEqual: ">=",
+}
+
+func (c *compiler) comparison(n Comparison) (string, error) {
+	field, ok := c.fields[n.Field]
+	if !ok {
+		return "", fmt.Errorf("filter: unknown field %q", n.Field)
+	}
+	if !field.allows(n.Operator) {
+		return "", fmt.Errorf("filter: operator %s is not allowed on %q", n.Operator, n.Field)
+	}
+	if field.Valid != nil {
+		for _, v := range n.Values {
+			if !field.Valid(v) {
+				return "", fmt.Errorf("filter: invalid value %q for %q", v, n.Field)
+			}
+		}
+	}
+
+	switch n.Operator {
+	case In, Out:
+		placeholders := make([]string, len(n.Values))
+		for i, v := range n.Values {
+			placeholders[i] = c.placeholder(v)
+		}
+		op := "IN"
+		if n.Operator == Out {
+			op = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", field.Column, op, strings.Join(placeholders, ", ")), nil
+	case Equal, NotEqual:
+		value := n.Values[0]
+		if strings.Contains(value, "*") {
+			if !field.Text {
+				return "", fmt.Errorf("filter: wildcards are not allowed on %q", n.Field)
+			}
+			op := "LIKE"
+			if n.Operator == NotEqual {
+				op = "NOT LIKE"
+			}
+			return fmt.Sprintf("%s %s %s", field.Column, op, c.placeholder(likePattern(value))), nil
+		}
+	}
+	return fmt.Sprintf("%s %s %s", field.Column, sqlOperators[n.Operator], c.placeholder(n.Values[0])), nil
+}
+
+// likePattern converts a `*` wildcard value into a LIKE pattern.
+func likePattern(value string) string {
+	return strings.ReplaceAll(EscapeLike(value), "*", "%")
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the characters LIKE treats specially, so s matches
+// literally inside a LIKE pattern.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
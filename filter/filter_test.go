@@ -0,0 +1,136 @@
+package filter
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Node
+	}{
+		{"name==foo", Comparison{"name", Equal, []string{"foo"}}},
+		{"id=gt=10", Comparison{"id", Greater, []string{"10"}}},
+		{"id>=10", Comparison{"id", GreaterOrEqual, []string{"10"}}},
+		{"id<10", Comparison{"id", Less, []string{"10"}}},
+		{"name!=foo*", Comparison{"name", NotEqual, []string{"foo*"}}},
+		{`name=="a b;c"`, Comparison{"name", Equal, []string{"a b;c"}}},
+		{`name=='it\'s'`, Comparison{"name", Equal, []string{"it's"}}},
+		{"id=in=(1,2,3)", Comparison{"id", In, []string{"1", "2", "3"}}},
+		{"name==a;id==1", And{
+			Comparison{"name", Equal, []string{"a"}},
+			Comparison{"id", Equal, []string{"1"}},
+		}},
+		{"name==a,name==b;id==1", Or{
+			Comparison{"name", Equal, []string{"a"}},
+			And{Comparison{"name", Equal, []string{"b"}}, Comparison{"id", Equal, []string{"1"}}},
+		}},
+		{"(name==a,name==b);id==1", And{
+			Or{Comparison{"name", Equal, []string{"a"}}, Comparison{"name", Equal, []string{"b"}}},
+			Comparison{"id", Equal, []string{"1"}},
+		}},
+		{"((name==a))", Comparison{"name", Equal, []string{"a"}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "id==1" + strings.Repeat(")", depth)
+	}
+	if _, err := Parse(nested(MaxDepth)); err != nil {
+		t.Errorf("Parse at MaxDepth: %v", err)
+	}
+
+	for _, expr := range []string{
+		"",
+		"name",
+		"name==",
+		"==foo",
+		"name=like=foo",
+		"name==foo;",
+		"name==foo,",
+		"(name==foo",
+		"name==foo)",
+		`name=="open`,
+		"id=in=1",
+		"id==(1,2)",
+		"id=in=(1,2",
+		"name==a b",
+		nested(MaxDepth + 1),
+		strings.Repeat("(", 100000),
+	} {
+		if node, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%.40q) = %#v, want an error", expr, node)
+		}
+	}
+}
+
+var testFields = map[string]Field{
+	"id": {Column: "id", Operators: []string{Equal, Greater, In, Out}, Valid: func(v string) bool {
+		_, err := strconv.Atoi(v)
+		return err == nil
+	}},
+	"name": {Column: "name", Text: true, Operators: []string{Equal, NotEqual, In}},
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		expr string
+		sql  string
+		args []any
+	}{
+		{"name==foo", "name = $3", []any{"foo"}},
+		{"name==foo*", "name LIKE $3", []any{"foo%"}},
+		{`name!=*50%_off*`, `name NOT LIKE $3`, []any{`%50\%\_off%`}},
+		{"id=gt=10", "id > $3", []any{"10"}},
+		{"id=out=(1,2)", "id NOT IN ($3, $4)", []any{"1", "2"}},
+		{"name==a;(id==1,id==2)", "(name = $3 AND (id = $4 OR id = $5))", []any{"a", "1", "2"}},
+	}
+	for _, tt := range tests {
+		node, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		sql, args, err := Compile(node, testFields, 2)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.expr, err)
+			continue
+		}
+		if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Compile(%q) = %q, %v; want %q, %v", tt.expr, sql, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"secret==1",         // unknown field
+		"name=gt=a",         // operator not allowed
+		"id==1*",            // wildcard on a non-text field
+		"id==abc",           // invalid value
+		"id=in=(1,x)",       // one invalid value in a list
+		"name==a;id==1.5",   // invalid value in a nested comparison
+		"id==1;(secret==2)", // unknown field in a group
+	} {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr, err)
+		}
+		if sql, _, err := Compile(node, testFields, 0); err == nil {
+			t.Errorf("Compile(%q) = %q, want an error", expr, sql)
+		}
+	}
+}
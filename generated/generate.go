@@ -0,0 +1,4 @@
+package generated
+
+//go:generate go run ../cmd/spec -o ../openapi/build/openapi.yaml
+//go:generate oapi-codegen -config ../openapi/codegen/server.yaml ../openapi/build/openapi.yaml
@@ -1,13 +1,22 @@
-// Package main provides primitives to interact with the openapi HTTP API.
+// Package generated provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
+// Code generated by github.com/deepmap/oapi-codegen version v1.16.2 DO NOT EDIT.
 package generated
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 
-	"github.com/labstack/echo/v4"
+	. "sample/models"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
 	"github.com/oapi-codegen/runtime"
 )
 
@@ -15,124 +24,647 @@ import (
 type ServerInterface interface {
 	// Get all items
 	// (GET /items)
-	GetItems(ctx echo.Context) error
+	GetItems(c *gin.Context, params GetItemsParams)
 	// Create an item
 	// (POST /items)
-	PostItems(ctx echo.Context) error
+	PostItems(c *gin.Context)
+	// Create several items at once
+	// (POST /items/bulk)
+	PostItemsBulk(c *gin.Context)
+	// Items within a radius of a point, nearest first
+	// (GET /items/nearby)
+	GetItemsNearby(c *gin.Context, params GetItemsNearbyParams)
+	// Full-text search over item names and descriptions
+	// (GET /items/search)
+	GetItemsSearch(c *gin.Context, params GetItemsSearchParams)
 	// Delete an item by ID
 	// (DELETE /items/{id})
-	DeleteItemsId(ctx echo.Context, id string) error
+	DeleteItemsId(c *gin.Context, id string, params DeleteItemsIdParams)
 	// Get an item by ID
 	// (GET /items/{id})
-	GetItemsId(ctx echo.Context, id string) error
+	GetItemsId(c *gin.Context, id string, params GetItemsIdParams)
+	// Partially update an item by ID
+	// (PATCH /items/{id})
+	PatchItemsId(c *gin.Context, id string, params PatchItemsIdParams)
 	// Update an item by ID
 	// (PUT /items/{id})
-	PutItemsId(ctx echo.Context, id string) error
+	PutItemsId(c *gin.Context, id string, params PutItemsIdParams)
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
+// ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandler       func(*gin.Context, error, int)
 }
 
-// GetItems converts echo context to params.
-func (w *ServerInterfaceWrapper) GetItems(ctx echo.Context) error {
+type MiddlewareFunc func(c *gin.Context)
+
+// GetItems operation middleware
+func (siw *ServerInterfaceWrapper) GetItems(c *gin.Context) {
+
 	var err error
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetItems(ctx)
-	return err
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsParams
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", c.Request.URL.Query(), &params.Filter)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter filter: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", c.Request.URL.Query(), &params.Name)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "description_contains" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "description_contains", c.Request.URL.Query(), &params.DescriptionContains)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter description_contains: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", c.Request.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sort: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", c.Request.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter created_after: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", c.Request.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter created_before: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "ids" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "ids", c.Request.URL.Query(), &params.Ids)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ids: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetItems(c, params)
+}
+
+// PostItems operation middleware
+func (siw *ServerInterfaceWrapper) PostItems(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostItems(c)
+}
+
+// PostItemsBulk operation middleware
+func (siw *ServerInterfaceWrapper) PostItemsBulk(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostItemsBulk(c)
+}
+
+// GetItemsNearby operation middleware
+func (siw *ServerInterfaceWrapper) GetItemsNearby(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsNearbyParams
+
+	// ------------- Required query parameter "lat" -------------
+
+	if paramValue := c.Query("lat"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument lat is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "lat", c.Request.URL.Query(), &params.Lat)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lat: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "lon" -------------
+
+	if paramValue := c.Query("lon"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument lon is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "lon", c.Request.URL.Query(), &params.Lon)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lon: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "radius" -------------
+
+	if paramValue := c.Query("radius"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument radius is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "radius", c.Request.URL.Query(), &params.Radius)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter radius: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetItemsNearby(c, params)
+}
+
+// GetItemsSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetItemsSearch(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsSearchParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := c.Query("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument q is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", c.Request.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter q: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetItemsSearch(c, params)
 }
 
-// PostItems converts echo context to params.
-func (w *ServerInterfaceWrapper) PostItems(ctx echo.Context) error {
+// DeleteItemsId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteItemsId(c *gin.Context) {
+
 	var err error
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostItems(ctx)
-	return err
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteItemsIdParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteItemsId(c, id, params)
 }
 
-// DeleteItemsId converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteItemsId(ctx echo.Context) error {
+// GetItemsId operation middleware
+func (siw *ServerInterfaceWrapper) GetItemsId(c *gin.Context) {
+
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsIdParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-None-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-None-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	// ------------- Optional header parameter "If-Modified-Since" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Modified-Since")]; found {
+		var IfModifiedSince string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Modified-Since, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Modified-Since", runtime.ParamLocationHeader, valueList[0], &IfModifiedSince)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Modified-Since: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfModifiedSince = &IfModifiedSince
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
 	}
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteItemsId(ctx, id)
-	return err
+	siw.Handler.GetItemsId(c, id, params)
 }
 
-// GetItemsId converts echo context to params.
-func (w *ServerInterfaceWrapper) GetItemsId(ctx echo.Context) error {
+// PatchItemsId operation middleware
+func (siw *ServerInterfaceWrapper) PatchItemsId(c *gin.Context) {
+
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
 	}
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetItemsId(ctx, id)
-	return err
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchItemsIdParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PatchItemsId(c, id, params)
 }
 
-// PutItemsId converts echo context to params.
-func (w *ServerInterfaceWrapper) PutItemsId(ctx echo.Context) error {
+// PutItemsId operation middleware
+func (siw *ServerInterfaceWrapper) PutItemsId(c *gin.Context) {
+
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutItemsIdParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
 	}
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PutItemsId(ctx, id)
-	return err
+	siw.Handler.PutItemsId(c, id, params)
 }
 
-// This is a simple interface which specifies echo.Route addition functions which
-// are present on both echo.Echo and echo.Group, since we want to allow using
-// either of them for path registration
-type EchoRouter interface {
-	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+// GinServerOptions provides options for the Gin server.
+type GinServerOptions struct {
+	BaseURL      string
+	Middlewares  []MiddlewareFunc
+	ErrorHandler func(*gin.Context, error, int)
 }
 
-// RegisterHandlers adds each server route to the EchoRouter.
-func RegisterHandlers(router EchoRouter, si ServerInterface) {
-	RegisterHandlersWithBaseURL(router, si, "")
+// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
+func RegisterHandlers(router gin.IRouter, si ServerInterface) {
+	RegisterHandlersWithOptions(router, si, GinServerOptions{})
 }
 
-// Registers handlers, and prepends BaseURL to the paths, so that the paths
-// can be served under a prefix.
-func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+// RegisterHandlersWithOptions creates http.Handler with additional options
+func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
+	errorHandler := options.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(c *gin.Context, err error, statusCode int) {
+			c.JSON(statusCode, gin.H{"msg": err.Error()})
+		}
+	}
 
 	wrapper := ServerInterfaceWrapper{
-		Handler: si,
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandler:       errorHandler,
 	}
 
-	router.GET(baseURL+"/items", wrapper.GetItems)
-	router.POST(baseURL+"/items", wrapper.PostItems)
-	router.DELETE(baseURL+"/items/:id", wrapper.DeleteItemsId)
-	router.GET(baseURL+"/items/:id", wrapper.GetItemsId)
-	router.PUT(baseURL+"/items/:id", wrapper.PutItemsId)
+	router.GET(options.BaseURL+"/items", wrapper.GetItems)
+	router.POST(options.BaseURL+"/items", wrapper.PostItems)
+	router.POST(options.BaseURL+"/items/bulk", wrapper.PostItemsBulk)
+	router.GET(options.BaseURL+"/items/nearby", wrapper.GetItemsNearby)
+	router.GET(options.BaseURL+"/items/search", wrapper.GetItemsSearch)
+	router.DELETE(options.BaseURL+"/items/:id", wrapper.DeleteItemsId)
+	router.GET(options.BaseURL+"/items/:id", wrapper.GetItemsId)
+	router.PATCH(options.BaseURL+"/items/:id", wrapper.PatchItemsId)
+	router.PUT(options.BaseURL+"/items/:id", wrapper.PutItemsId)
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xaa2/jNtb+Kwd6X6Azrew4mbSd2pgPadLpejGXbC7YAt2iocUjmw1FakjKE2OQ/744",
+	"JGVblpw4SS+z2P0UR6TIh+c85yp+SjJdlFqhcjYZfkpmyDga//OHCzalvxxtZkTphFbJMDmujEHlYI7G",
+	"Cq1A5+BmCMJhkUKuDVQWQSgY5723zGWzfpImNpthwWgttygxGSbWGaGmye1tmrxh1r3VXOQCeXu3C1Hg",
+	"+hZfWJDMOshmTE0RnlUlZw75r8w9T4FZYAr+dnFxCvT07p1v06RkhhXo4nHHucfbxkByqDHUp6bfEYOw",
+	"MGEWOWjlIRh0lVHIYbKAH3+4SEEbuPryCpwGVpZyAcL532pRr9aHfxrh0MJH4Wa6cjSDGQSDeUUL02M4",
+	"PHjZ/5dK0kQQpqCmJE0UK+hYtbTvO7JBW2pl0Z/41GCmFRd0ztdMyKCATCuHytFPwisyRuN7pdETicVX",
+	"v1kSyqe1bf7fYJ4Mk//bWzFpL4zavdPwVth8Q7VRpTBjNsqSgxUqw4akp2KOqkmo27QB/Qw/VML8+eAN",
+	"fqjQOo9f6SU+CLrpe3bHpWin7yt5fYa2kh5daXSJxomgCTRGGw+ahzMxebo2wZkKNxFEcMDRMSHt0kLg",
+	"o64khxmbE8IMxRw55EYXcPr+/AL2aI4l04js0JPfMHMkUxq5TyRjmkPncsxVtm0r5/75o8EI5XCKJglU",
+	"rdX6c73dLx2oxxF1U6CZwegX6L9cm4J+JeQWek4UmND6jL9XclGLd8NaNuT9qT0uOvzVkbViGo2fhGDR",
+	"zNEMgYEltignmIR4Su8ZGFxejk9AK+BYSr0oSNyQaZWLaWVq4/dzrnFhgwu4F7pkTriKYxvgmzhCFsVx",
+	"ahDtCCySS5qim6EJG0qtpn4eKWclPl1NJK7UpapiQtpKk+X8jh3rofu2ZO4hOwbH16GWVUh4vOqj82kf",
+	"Zqwyg6Qj7+8B52gW0XmN1oMUxYzGQYRy3xxu33qd+C2Kv0NmJottvoML65jKOiR/EkeCxbmVy0IOpRbK",
+	"kUYKdAbtjkLf3UVsGLB/MV1h7bLk2t0+yQ0+O3t9DN++HHz7PAWLyvmkYEskICMNGvT+F+rg2IdzXSDE",
+	"qRYyZmjOjTMMCiRZ2BRslc1o8XpjKawTagpzJgX3e0HOhKwMRqPd0Jp/rduvqG0KPWVuVmcidfRxM+b8",
+	"TsjXHOlqtZWn3qRamjjhZLcRhQeb+1+ejUFwVE7kCzor4bgWihOmKK0RsImu3HAimbqGjzMMuVJAAZYt",
+	"rM9upOwAu8EZP1qDTO+KAefITDbbZiAUj6VQHefx8Ro5OLxxwQkFn02rgUNTWJiJ6UyK6cxtEe9DgqZh",
+	"6roN4gwlzr2Z2kwbHMFgTWwBilAcb0BYqBSbMyHZRDbdZC41c22D7TZCDyNdiaUt0VvPwlx3gS0Nkl15",
+	"hlufpkZnGYJegVwwoPX6cI6Kw9VRlmHphg0rnCveD7F/vu9N0afHpVDA6uVGUEomVOM1mglTdCHBkMyR",
+	"AWiFfbhUtipLbci1xQVsRw49+MYH3cP9r32pUtvQRHNRm2m0ieRcFKVEOD67PIGj03GyFhSS/f6gPyCN",
+	"6hIVK0UyTF74R1RTuJlnXcht6NcUPSmJkv4YY54Mkx/Rjf2EZhnyc0vg5/94A7mQDg3gDQmfMKSA/Wkf",
+	"rigIvnqVa/3lSPBXU/dqf3DVh/OlKHKBkgdBCJ4CTQemOKztMYKr0RVx6+jdSQpXqf/9/myt1vhQoVms",
+	"So0A5u6qbvMUFPFiYeQjZCh1AG9Y5iRlSsJ6cP0te/o/T91xpi2un5yyLMeEsmF/7wSeZcxiTyiLygon",
+	"5vh8G6S1hX6tF3oYxGNdFKxnkfRPyrLauKixFEqDubipidvzdKXXUXHyvNpwNDULegQpFZxUr40j79DU",
+	"vNe5ly+cYM4qSRakQfDtWiYwDzvOW3YjiqqA4HwoJAS5O13r4RkPm8PXgxSYg0JbBweDwVYZS1GIJoq4",
+	"QjL8epAmRdgxGR4M6D+hwn/7XVnVJtp3bZT2WpQpCEUC8/LdBkvnucUtuNaBDHYB8r50IJTTkFXGagPP",
+	"KM1H9xxKNhXKO4w+nDLrextYlG5BKUaFnhHkBnNhrKPZmIKrA8dPvXd443rHYc3wQkwcSoNzoSvrX+nD",
+	"MVNKO5ggZLqYCFVTLhxyBD/1LrRjsnesK+WA7FS7ZY9jO3/CaZ5qs7GMA5Y7NNFMxXY3saz68k0P1VkG",
+	"PAHQBHNtcHdEYf7vAOk1UovBzdCGWsPWTkBw++pFup8eXKVL9RCZm2m/5/UIKnWt9EdienARRP0SeR+O",
+	"VlZJg54ebW4E2lEYsDCpnKeEHyCvkYI32zQyiOJtIEPq3dAOfMKbUmqOyTBn0mK3YAVvettltG1laAW7",
+	"CZE2uIk4zoxhi5AfL3y8J3Ukt79stMgOBoM72krtdtISxi75YBNKu8n0Rli39FAxY4vNyoZ9dzZpye5r",
+	"F6EotJG5EyGinyk0R0rWfZEUO5q+terdwt2GmzSUmAzvc64F5dl1wRCIk4KYKk0LBrp4bgTG9GHN7gKJ",
+	"w4DHHCjSArfysF6QtioKZhYh0aJyIwqRur7aduRjp9ouE7JoLt9rvniQ8neoils92IPB/u++xyaPjqPT",
+	"EnVlvpJOGKLQshwMievepJK+WKnF1bWkhaqkyLlP3sJrmnSlEJxhyrIsBK9x7pvdNIHs3SAVGUjpKE0l",
+	"9xO95Cg6qyCeEMwMUiobEn6qcokwYSsiTMHMdRjTboYmppWx2Dw8OAxk2aJpasg+QdsPMvWVE9qPuUr9",
+	"b9sNPJUiOwFb60bv4ImOagOCj7jSl0+W6hrKRxZa6/Dg4C+H60AiC7Vh7EOzFfFCNqyd90g0EI/TbRqW",
+	"OkSsPj6jNTNcNxTlu3Nrhd5Gx3BZ7tCXnUxrw4XytkPMV7T6Kvwl6ZYyMbQA28ViZ9bsWwGrwj+0zboS",
+	"j7rPt0ykv1tPX3vfDTo6Clv21Oqxe+6/bGy6/7J7143vDKExYhgXlW22MbvghXkPRdjO47cDalc/xtP1",
+	"M6l//pS8ptGn3sFM39ZpQUxkyZLQulDRbFjjyox8kygqXufAQiu76+1ooKGJdm8nJlDqvn7Med0cvPHd",
+	"8484qZt0dqEcu4FnHyrtG+wzwyzaFN6fpdBzaIqtav5wJzEfUXj/91Gv0QF+BPW8PEzoxHYT8HUlZc+r",
+	"Pepbk+em131nxW721ew6BT8JfhtkKNFhm4Mn/rmn4Zhv8fLUWVyvfx5Kmi7xrfbZq68/dOjrsCPD11Ar",
+	"kEL+/sE2BS2X2uu4beCzhZcPe3X5tb+pniDBOpGlTvT4hNa/0+T/MFm3L5CELDWTwn+TkgYZX8CM2RGw",
+	"UB5FS7XwYnDYv+OaxzutcIe7Hi0Qy2sxo/WdQKzu1sSLDK55GaMPYyrUqPSnfLyBAIT1H9nuuZgSb/f0",
+	"zmm9O1E/1Vc8pjqi5/VXvGaRXV+A6lo1Ttvzc+I1pt76Paa7XmrcefKQXnQZ2fKGjCcIrlPoCwuZLhd/",
+	"Ed52gb1pdmX3Vaoj0h75Svj7+ft3UKCZIvi58cPti+++eT4ErWS8PlH5FxofMiI7QzeJhZF4nQBURYWK",
+	"hUwiM9TPEhyyVYMzvNlZFBKGz8j/7lKSeun1vPS++r2aEX+8uV2G6xkQP0Y+ir63n0fMOWXGCSblAsKd",
+	"kw4zqLpaTZX7D2Pa/9j1F7DrspNTt7e3/x4AjkSMVbMrAAA=",
+}
 
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
 }
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sample/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBatchSize caps the number of sub-requests accepted by POST /batch.
+const MaxBatchSize = 50
+
+// BatchRequest is a single sub-request of a POST /batch call.
+type BatchRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// BatchResponse is the outcome of a single sub-request.
+type BatchResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Batch returns a handler that executes an array of sub-requests against
+// router, one after another, and responds with their statuses and bodies in
+// the same order. A failing sub-request does not stop the ones after it.
+func Batch(router http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requests []BatchRequest
+		if err := c.ShouldBindJSON(&requests); err != nil {
+			writeError(c, problem.New(http.StatusBadRequest, err.Error()))
+			return
+		}
+		if len(requests) == 0 || len(requests) > MaxBatchSize {
+			writeError(c, problem.Newf(http.StatusBadRequest, "a batch must contain between 1 and %d requests", MaxBatchSize))
+			return
+		}
+		subrequests := make([]*http.Request, len(requests))
+		for i, r := range requests {
+			req, err := newBatchRequest(c, r)
+			if err != nil {
+				writeError(c, problem.Newf(http.StatusBadRequest, "request %d: %v", i, err))
+				return
+			}
+			if req.URL.Path == c.FullPath() {
+				writeError(c, problem.Newf(http.StatusBadRequest, "request %d: batches cannot be nested", i))
+				return
+			}
+			subrequests[i] = req
+		}
+
+		responses := make([]BatchResponse, len(requests))
+		for i, req := range subrequests {
+			responses[i] = runBatchRequest(router, req)
+		}
+		c.JSON(http.StatusOK, responses)
+	}
+}
+
+// batchHeaders are the sub-response headers passed back to the caller.
+var batchHeaders = []string{"ETag", "Last-Modified", "Location", "Warning", "X-Next-Cursor", "X-Total-Count"}
+
+// newBatchRequest builds the sub-request for r. The path must be
+// origin-relative; it is checked only once parsed, so escapes such as
+// %62 cannot disguise the route it resolves to.
+func newBatchRequest(c *gin.Context, r BatchRequest) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(c.Request.Context(), strings.ToUpper(r.Method), r.Path, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.Path, "/") || req.URL.Host != "" {
+		return nil, fmt.Errorf("invalid path %q", r.Path)
+	}
+	if len(r.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", c.GetHeader("Accept"))
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+func runBatchRequest(router http.Handler, req *http.Request) BatchResponse {
+	rec := &batchRecorder{header: http.Header{}, status: http.StatusOK}
+	router.ServeHTTP(rec, req)
+
+	body := rec.body.Bytes()
+	if len(body) > 0 && !json.Valid(body) {
+		body, _ = json.Marshal(string(body))
+	}
+	var headers map[string]string
+	for _, name := range batchHeaders {
+		if values := rec.header.Values(name); len(values) > 0 {
+			if headers == nil {
+				headers = map[string]string{}
+			}
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return BatchResponse{Status: rec.status, Headers: headers, Body: body}
+}
+
+// batchRecorder captures a sub-request's response in memory.
+type batchRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *batchRecorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
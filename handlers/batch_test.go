@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBatchRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/batch", Batch(router))
+	router.GET("/things", func(c *gin.Context) {
+		c.Header("X-Total-Count", "3")
+		c.Header("X-Next-Cursor", "abc")
+		c.Writer.Header().Add("Warning", `299 - "one"`)
+		c.Writer.Header().Add("Warning", `299 - "two"`)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, []string{})
+	})
+	router.POST("/things", func(c *gin.Context) {
+		c.Header("Location", "/things/1")
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+	})
+	return router
+}
+
+func TestBatchRejectsNestedBatches(t *testing.T) {
+	router := newBatchRouter()
+	for _, path := range []string{"/batch", "/%62atch", "/batch?x=1", "//batch", "http://example.com/things", "things"} {
+		rec := serve(router, http.MethodPost, "/batch", `[{"method": "POST", "path": "`+path+`", "body": []}]`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("path %q: status = %d, want 400: %s", path, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestBatchRunsSimilarPaths(t *testing.T) {
+	rec := serve(newBatchRouter(), http.MethodPost, "/batch", `[{"method": "GET", "path": "/batchx"}]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var responses []BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0].Status != http.StatusNotFound {
+		t.Errorf("responses = %+v, want one 404", responses)
+	}
+}
+
+func TestBatchPassesHeadersThrough(t *testing.T) {
+	rec := serve(newBatchRouter(), http.MethodPost, "/batch",
+		`[{"method": "GET", "path": "/things"}, {"method": "POST", "path": "/things", "body": {}}]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var responses []BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]string{
+		{"X-Total-Count": "3", "X-Next-Cursor": "abc", "Warning": `299 - "one", 299 - "two"`},
+		{"Location": "/things/1", "ETag": `"1"`},
+	}
+	if len(responses) != len(want) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(want))
+	}
+	for i, r := range responses {
+		if !reflect.DeepEqual(r.Headers, want[i]) {
+			t.Errorf("response %d headers = %v, want %v", i, r.Headers, want[i])
+		}
+	}
+}
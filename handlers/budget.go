@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"log"
+	"sample/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementBudget counts the SQL statements issued while serving each
+// request and logs requests that go over limit, which usually points at an
+// N+1 query pattern. With enforce set the statements past the limit fail.
+func StatementBudget(limit int64, enforce bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := db.WithStatementBudget(c.Request.Context(), limit, enforce)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if used := db.StatementsUsed(ctx); used > limit {
+			log.Printf("statement budget exceeded: %s %s issued %d statements (budget %d)", c.Request.Method, c.FullPath(), used, limit)
+		}
+	}
+}
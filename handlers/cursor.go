@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"sample/db"
+	"strings"
+)
+
+// cursorPrefix versions the cursor format so it can change without old
+// tokens being misread.
+const cursorPrefix = "v1:"
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor returns the opaque token that resumes a listing after the
+// item with the given id.
+func encodeCursor(afterID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + afterID))
+}
+
+// decodeCursor returns the id encoded in token. Tokens are not signed, so
+// the id is checked like any client input before it reaches a query.
+func decodeCursor(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", errInvalidCursor
+	}
+	id, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok || !db.ValidID(id) {
+		return "", errInvalidCursor
+	}
+	return id, nil
+}
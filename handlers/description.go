@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"strings"
+	"text/template"
+
+	"sample/models"
+)
+
+// DescriptionTemplate, when set, generates the description of items that
+// are written without one. It is executed with the item as data, e.g.
+// `{{.Name}} (auto-generated)`. Fields are pointers, so guard optional ones
+// with {{with}} to avoid rendering "<nil>".
+var DescriptionTemplate *template.Template
+
+// fillDescription sets item.Description from DescriptionTemplate when the
+// client left it out.
+func fillDescription(item *models.Item) error {
+	if DescriptionTemplate == nil || item.Description != nil {
+		return nil
+	}
+	var b strings.Builder
+	if err := DescriptionTemplate.Execute(&b, item); err != nil {
+		return err
+	}
+	description := b.String()
+	item.Description = &description
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !itemjson
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sample/models"
+)
+
+// writeItems renders a list of items. Build with -tags itemjson to use the
+// reflection-free encoder instead.
+func writeItems(w http.ResponseWriter, status int, items []models.Item) error {
+	body, err := json.Marshal(collection(items))
+	if err != nil {
+		return err
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,39 @@
+//go:build itemjson
+
+package handlers
+
+import (
+	"net/http"
+	"sample/models"
+	"sync"
+)
+
+// maxPooledBuffer keeps buffers from unusually large responses out of the
+// pool so one big listing does not pin its memory for good.
+const maxPooledBuffer = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 16<<10)
+		return &buf
+	},
+}
+
+// writeItems renders a list of items with the hand-written encoder in the
+// models package, avoiding encoding/json's reflection on the hot list path.
+// Output buffers are recycled through bufferPool.
+func writeItems(w http.ResponseWriter, status int, items []models.Item) error {
+	bufp := bufferPool.Get().(*[]byte)
+	buf := models.AppendItemsJSON((*bufp)[:0], collection(items))
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	_, err := w.Write(buf)
+
+	if cap(buf) <= maxPooledBuffer {
+		*bufp = buf
+		bufferPool.Put(bufp)
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sample/db"
+	"sample/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// toProblem returns the problem details to report err with. It is the one
+// place errors are mapped to statuses: handlers return *problem.Problem for
+// errors the client caused, and database errors are classified here.
+// Anything unrecognised becomes a 500 whose detail does not leak the
+// underlying error.
+func toProblem(err error) *problem.Problem {
+	var p *problem.Problem
+	if errors.As(err, &p) {
+		return p
+	}
+	err = db.Classify(err)
+	var missing *db.MissingValueError
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		// Not-found errors are the db package's own, so they are safe to show.
+		return problem.New(http.StatusNotFound, err.Error())
+	case errors.Is(err, db.ErrVersionMismatch):
+		return problem.New(http.StatusPreconditionFailed, db.ErrVersionMismatch.Error())
+	case errors.Is(err, db.ErrConflict):
+		return problem.New(http.StatusConflict, db.ErrConflict.Error())
+	case errors.Is(err, db.ErrInvalidReference):
+		return problem.New(http.StatusUnprocessableEntity, db.ErrInvalidReference.Error())
+	case errors.As(err, &missing):
+		return problem.New(http.StatusUnprocessableEntity, missing.Error()).With("field", missing.Column)
+	}
+	return problem.New(http.StatusInternalServerError, "the request could not be completed because of an internal error")
+}
+
+// writeError reports err to the client as application/problem+json. Server
+// errors are logged in full, since the client only sees a generic detail.
+func writeError(c *gin.Context, err error) {
+	p := toProblem(err)
+	if p.Status >= http.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	problem.Write(c.Writer, c.Request, p)
+}
+
+// abortWithError is writeError for middleware: it also stops the handlers
+// after it from running.
+func abortWithError(c *gin.Context, err error) {
+	writeError(c, err)
+	c.Abort()
+}
+
+// RouteNotFound answers requests no route matches, in the same format as
+// every other error.
+func RouteNotFound(c *gin.Context) {
+	writeError(c, problem.Newf(http.StatusNotFound, "no route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
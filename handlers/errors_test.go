@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestToProblemNotNullViolation(t *testing.T) {
+	p := toProblem(&pq.Error{Code: "23502", Column: "name", Message: `null value in column "name"`})
+	if p.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", p.Status)
+	}
+	if got := p.Members()["field"]; got != "name" {
+		t.Errorf("field = %v, want name", got)
+	}
+}
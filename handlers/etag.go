@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/models"
+	"sample/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errIfMatchRequired = errors.New("If-Match header is required; send the ETag returned by GET /items/{id}, or * to overwrite any version")
+
+// itemETag returns the strong entity tag for an item, derived from its
+// version.
+func itemETag(item models.Item) string {
+	if item.Version == nil {
+		return ""
+	}
+	return `"` + strconv.FormatInt(*item.Version, 10) + `"`
+}
+
+// setValidators sends the ETag and Last-Modified headers of an item, the
+// validators clients use for conditional requests.
+func setValidators(c *gin.Context, item models.Item) {
+	if tag := itemETag(item); tag != "" {
+		c.Header("ETag", tag)
+	}
+	if item.UpdatedAt != nil {
+		c.Header("Last-Modified", item.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified evaluates the preconditions of a conditional GET against
+// item, following RFC 9110: If-None-Match takes precedence, using weak
+// comparison, and If-Modified-Since is only consulted without it.
+func notModified(item models.Item, ifNoneMatch, ifModifiedSince *string) bool {
+	if ifNoneMatch != nil {
+		tag := itemETag(item)
+		for _, candidate := range strings.Split(*ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || (tag != "" && strings.TrimPrefix(candidate, "W/") == tag) {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince != nil && item.UpdatedAt != nil {
+		since, err := http.ParseTime(*ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have one-second resolution.
+		return !item.UpdatedAt.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// ifMatch returns the versions a write's If-Match header accepts, or nil
+// when it is "*". Writes must carry the header, so a missing one is a 428.
+// Weak and malformed tags never match, as If-Match uses strong comparison.
+func ifMatch(header *string) ([]int64, error) {
+	if header == nil {
+		return nil, problem.New(http.StatusPreconditionRequired, errIfMatchRequired.Error())
+	}
+	if strings.TrimSpace(*header) == "*" {
+		return nil, nil
+	}
+	versions := []int64{}
+	for _, tag := range strings.Split(*header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
@@ -3,43 +3,191 @@ package handlers
 import (
 	"net/http"
 	"sample/db"
-
 	"sample/models"
+	"sample/problem"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetItems(c *gin.Context) {
-	rows, err := db.DB.Query("SELECT id, name, description FROM items")
+// Server implements generated.ServerInterface on top of the db package, so
+// the routes served for /items are the ones declared in the OpenAPI spec.
+type Server struct{}
+
+func (Server) GetItems(c *gin.Context, params models.GetItemsParams) {
+	page, err := listItems(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
-	defer rows.Close()
+	if page.Total != nil {
+		c.Header("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+	if page.NextCursor != "" {
+		c.Header("X-Next-Cursor", page.NextCursor)
+	}
+	writeItems(c.Writer, http.StatusOK, page.Items)
+}
 
+func (Server) PostItemsBulk(c *gin.Context) {
 	var items []models.Item
-	for rows.Next() {
-		var item models.Item
-		if err := rows.Scan(&item.Id, &item.Name, &item.Description); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		items = append(items, item)
+	if err := bindJSON(c, &items); err != nil {
+		writeError(c, err)
+		return
+	}
+	if len(items) == 0 || len(items) > MaxBulkSize {
+		writeError(c, problem.Newf(http.StatusBadRequest, "a bulk request must contain between 1 and %d items", MaxBulkSize))
+		return
+	}
+
+	results, ok, err := createItems(c.Request.Context(), items)
+	if err != nil {
+		writeError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, items)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, results)
+		return
+	}
+	c.JSON(http.StatusCreated, results)
 }
 
-func CreateItem(c *gin.Context) {
-	var item models.Item
-	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (Server) GetItemsSearch(c *gin.Context, params models.GetItemsSearchParams) {
+	if strings.TrimSpace(params.Q) == "" {
+		writeError(c, problem.New(http.StatusBadRequest, "q must not be empty"))
+		return
+	}
+	limit, err := resultLimit(params.Limit)
+	if err != nil {
+		writeError(c, problem.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	hits, err := db.SearchItems(c.Request.Context(), params.Q, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	results := make([]models.SearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = models.SearchResult{Item: hit.Item, Rank: hit.Rank, Headline: hit.Headline}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (Server) GetItemsNearby(c *gin.Context, params models.GetItemsNearbyParams) {
+	if err := validateCoordinates(params.Lat, params.Lon); err != nil {
+		writeError(c, problem.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+	if params.Radius < 0 {
+		writeError(c, problem.New(http.StatusBadRequest, "radius must not be negative"))
+		return
+	}
+	limit, err := resultLimit(params.Limit)
+	if err != nil {
+		writeError(c, problem.New(http.StatusBadRequest, err.Error()))
 		return
 	}
 
-	err := db.DB.QueryRow("INSERT INTO items (name, description) VALUES ($1, $2) RETURNING id", item.Name, item.Description).Scan(&item.Id)
+	hits, err := db.NearbyItems(c.Request.Context(), params.Lat, params.Lon, params.Radius, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
+		return
+	}
+	results := make([]models.NearbyResult, len(hits))
+	for i, hit := range hits {
+		results[i] = models.NearbyResult{Item: hit.Item, Distance: hit.Distance}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (Server) PostItems(c *gin.Context) {
+	var item models.Item
+	if err := bindJSON(c, &item); err != nil {
+		writeError(c, err)
 		return
 	}
+
+	if err := createItem(c.Request.Context(), &item); err != nil {
+		writeError(c, err)
+		return
+	}
+	setValidators(c, item)
 	c.JSON(http.StatusCreated, item)
 }
+
+func (Server) GetItemsId(c *gin.Context, id string, params models.GetItemsIdParams) {
+	item, err := db.GetItem(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setValidators(c, item)
+	if notModified(item, params.IfNoneMatch, params.IfModifiedSince) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (Server) PutItemsId(c *gin.Context, id string, params models.PutItemsIdParams) {
+	versions, err := ifMatch(params.IfMatch)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	var item models.Item
+	if err := bindJSON(c, &item); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if err := updateItem(c.Request.Context(), id, versions, &item); err != nil {
+		writeError(c, err)
+		return
+	}
+	setValidators(c, item)
+	c.JSON(http.StatusOK, item)
+}
+
+func (Server) PatchItemsId(c *gin.Context, id string, params models.PatchItemsIdParams) {
+	versions, err := ifMatch(params.IfMatch)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	var patch models.ItemPatch
+	if err := bindJSON(c, &patch); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	item, err := patchItem(c.Request.Context(), id, versions, patch)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setValidators(c, item)
+	c.JSON(http.StatusOK, item)
+}
+
+func (Server) DeleteItemsId(c *gin.Context, id string, params models.DeleteItemsIdParams) {
+	versions, err := ifMatch(params.IfMatch)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if err := db.DeleteItem(c.Request.Context(), id, versions); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ParameterError reports request parameters the generated wrapper could
+// not bind, in the same shape as the handlers' own errors.
+func ParameterError(c *gin.Context, err error, status int) {
+	writeError(c, problem.New(status, err.Error()))
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"sample/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds the database ping behind Readyz.
+const readinessTimeout = 2 * time.Second
+
+// Healthz reports that the process is up and serving requests.
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the service can handle traffic, which requires a
+// reachable database.
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	if err := db.DB.PingContext(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sample/db"
+	"sample/filter"
+	"sample/models"
+	"sample/problem"
+	"sort"
+	"strings"
+)
+
+// itemFilterFields whitelists the fields and operators accepted by the
+// filter query parameter of GetItems.
+var itemFilterFields = map[string]filter.Field{
+	"id": {Column: "id", Indexed: true, Valid: db.ValidID, Operators: []string{
+		filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual,
+		filter.Greater, filter.GreaterOrEqual, filter.In, filter.Out,
+	}},
+	"name": {Column: "name", Text: true, Operators: []string{
+		filter.Equal, filter.NotEqual, filter.In, filter.Out,
+	}},
+	"description": {Column: "description", Text: true, Operators: []string{
+		filter.Equal, filter.NotEqual,
+	}},
+}
+
+func indexedFields(fields map[string]filter.Field) []string {
+	var names []string
+	for name, f := range fields {
+		if f.Indexed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// itemSortColumns whitelists the fields GET /items can be sorted by.
+var itemSortColumns = map[string]string{
+	"id":   "id",
+	"name": "name",
+}
+
+// parseSort turns a sort parameter such as "name" or "-name,id" into ORDER
+// BY terms, rejecting fields that are not whitelisted.
+func parseSort(sort string) ([]string, error) {
+	var terms []string
+	for _, field := range strings.Split(sort, ",") {
+		direction := "ASC"
+		if name, ok := strings.CutPrefix(field, "-"); ok {
+			field, direction = name, "DESC"
+		}
+		column, ok := itemSortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("cannot sort by %q", field)
+		}
+		terms = append(terms, column+" "+direction)
+	}
+	return terms, nil
+}
+
+// likeContains returns a LIKE pattern matching values containing s.
+func likeContains(s string) string {
+	return "%" + filter.EscapeLike(s) + "%"
+}
+
+// Page size bounds for GET /items. The spec rejects a limit above
+// MaxPageSize, so handlers never see one.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+	// MaxBulkSize caps the number of items accepted by POST /items/bulk.
+	MaxBulkSize = 100
+)
+
+// itemPage is one page of a GET /items listing.
+type itemPage struct {
+	Items []models.Item
+	// Total is the number of items matching the filter. It is only
+	// computed in offset mode.
+	Total *int64
+	// NextCursor resumes the listing after this page in cursor mode. It is
+	// empty on the last page.
+	NextCursor string
+}
+
+// listItems returns the page of items selected by params. Passing a cursor
+// (empty for the first page) switches from offset to keyset pagination,
+// which stays fast however deep the client pages.
+func listItems(ctx context.Context, params models.GetItemsParams) (itemPage, error) {
+	var page itemPage
+	q := db.ItemQuery{Limit: DefaultPageSize}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			return page, problem.New(http.StatusBadRequest, "limit must be at least 1")
+		}
+		q.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		if *params.Offset < 0 {
+			return page, problem.New(http.StatusBadRequest, "offset must not be negative")
+		}
+		q.Offset = *params.Offset
+	}
+
+	if params.Sort != nil && *params.Sort != "" {
+		terms, err := parseSort(*params.Sort)
+		if err != nil {
+			return page, problem.New(http.StatusBadRequest, err.Error())
+		}
+		q.OrderBy = terms
+	}
+	if params.Name != nil {
+		q.Where(fmt.Sprintf("name = $%d", q.ArgCount()+1), *params.Name)
+	}
+	if params.DescriptionContains != nil && *params.DescriptionContains != "" {
+		q.Where(fmt.Sprintf("description ILIKE $%d", q.ArgCount()+1), likeContains(*params.DescriptionContains))
+	}
+
+	if params.CreatedAfter != nil {
+		q.Where(fmt.Sprintf("created_at > $%d", q.ArgCount()+1), *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		q.Where(fmt.Sprintf("created_at < $%d", q.ArgCount()+1), *params.CreatedBefore)
+	}
+
+	idsMode := params.Ids != nil
+	if idsMode {
+		if params.Sort != nil || params.Limit != nil || params.Offset != nil || params.Cursor != nil {
+			return page, problem.New(http.StatusBadRequest, "ids cannot be combined with sort, limit, offset or cursor")
+		}
+		ids, err := parseIDs(*params.Ids)
+		if err != nil {
+			return page, problem.New(http.StatusBadRequest, err.Error())
+		}
+		q.WhereIDs(ids)
+		q.Limit = len(ids)
+	}
+
+	cursorMode := params.Cursor != nil
+	if cursorMode {
+		if len(q.OrderBy) > 0 && q.OrderBy[0] != "id ASC" {
+			return page, problem.New(http.StatusBadRequest, "cursor pagination only supports sorting by id")
+		}
+		if q.Offset > 0 {
+			return page, problem.New(http.StatusBadRequest, "cursor and offset cannot be combined")
+		}
+		if *params.Cursor != "" {
+			afterID, err := decodeCursor(*params.Cursor)
+			if err != nil {
+				return page, problem.New(http.StatusBadRequest, err.Error())
+			}
+			q.Where(fmt.Sprintf("id > $%d", q.ArgCount()+1), afterID)
+		}
+	}
+
+	if params.Filter != nil && *params.Filter != "" {
+		node, err := filter.Parse(*params.Filter)
+		if err != nil {
+			return page, problem.New(http.StatusBadRequest, err.Error())
+		}
+		where, args, err := filter.Compile(node, itemFilterFields, q.ArgCount())
+		if err != nil {
+			return page, problem.New(http.StatusBadRequest, err.Error())
+		}
+		q.Where(where, args...)
+
+		if db.ScanRowLimit > 0 {
+			scanned, err := db.ListItemsScanRows(ctx, q)
+			if err != nil {
+				return page, err
+			}
+			if scanned > db.ScanRowLimit {
+				return page, problem.Newf(http.StatusUnprocessableEntity, "filter would scan about %.0f rows; narrow it with an index-backed field", scanned).
+					With("indexed_fields", indexedFields(itemFilterFields))
+			}
+		}
+	}
+
+	if idsMode {
+		items, err := db.ListItems(ctx, q)
+		if err != nil {
+			return page, err
+		}
+		page.Items = items
+		return page, nil
+	}
+
+	if cursorMode {
+		// Fetch one extra row to learn whether another page follows.
+		limit := q.Limit
+		q.Limit++
+		items, err := db.ListItems(ctx, q)
+		if err != nil {
+			return page, err
+		}
+		if len(items) > limit {
+			items = items[:limit]
+			page.NextCursor = encodeCursor(*items[limit-1].Id)
+		}
+		page.Items = items
+		return page, nil
+	}
+
+	items, err := db.ListItems(ctx, q)
+	if err != nil {
+		return page, err
+	}
+	total, err := db.CountItems(ctx, q)
+	if err != nil {
+		return page, err
+	}
+	page.Items, page.Total = items, &total
+	return page, nil
+}
+
+// parseIDs checks the ids parameter of GetItems and drops repeated ids,
+// keeping the first occurrence so the requested order is preserved.
+func parseIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 || len(ids) > MaxPageSize {
+		return nil, fmt.Errorf("ids must list between 1 and %d ids", MaxPageSize)
+	}
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !db.ValidID(id) {
+			return nil, fmt.Errorf("ids: %q is not a valid id", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// resultLimit applies DefaultPageSize to the limit parameter of endpoints
+// that return a single page of results.
+func resultLimit(limit *int) (int, error) {
+	if limit == nil {
+		return DefaultPageSize, nil
+	}
+	if *limit < 1 {
+		return 0, errors.New("limit must be at least 1")
+	}
+	return *limit, nil
+}
+
+// validateCoordinates checks that lat/lon is a point on the globe.
+func validateCoordinates(lat, lon float64) error {
+	if lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90, got %g", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180, got %g", lon)
+	}
+	return nil
+}
+
+// prepareItem applies the server-side defaults and validation hooks that
+// every item write goes through.
+func prepareItem(ctx context.Context, item *models.Item) error {
+	if err := fillDescription(item); err != nil {
+		return err
+	}
+	return validateItem(ctx, *item)
+}
+
+// createItem prepares and inserts item.
+func createItem(ctx context.Context, item *models.Item) error {
+	if err := prepareItem(ctx, item); err != nil {
+		return err
+	}
+	return db.CreateItem(ctx, item)
+}
+
+// createItems prepares every item and inserts them all, or none if any item
+// is rejected, by a validation hook or by the database. The returned results
+// line up with items; ok is false when nothing was created, in which case
+// the items that were not at fault get 424. Other errors abort the call.
+func createItems(ctx context.Context, items []models.Item) (results []models.BulkResult, ok bool, err error) {
+	results = make([]models.BulkResult, len(items))
+	ok = true
+	for i := range items {
+		if err := prepareItem(ctx, &items[i]); err != nil {
+			var p *problem.Problem
+			if !errors.As(err, &p) {
+				return nil, false, err
+			}
+			results[i] = rejectedResult(p)
+			ok = false
+		}
+	}
+
+	if ok {
+		err := db.CreateItems(ctx, items)
+		var bulk *db.BulkError
+		switch {
+		case errors.As(err, &bulk):
+			for i, itemErr := range bulk.Errs {
+				if itemErr == nil {
+					continue
+				}
+				p := toProblem(itemErr)
+				if p.Status >= http.StatusInternalServerError {
+					return nil, false, err
+				}
+				results[i] = rejectedResult(p)
+			}
+			ok = false
+		case err != nil:
+			return nil, false, err
+		}
+	}
+
+	if !ok {
+		for i := range results {
+			if results[i].Status == 0 {
+				results[i].Status = http.StatusFailedDependency
+			}
+		}
+		return results, false, nil
+	}
+	for i := range items {
+		results[i] = models.BulkResult{Status: http.StatusCreated, Item: &items[i]}
+	}
+	return results, true, nil
+}
+
+func rejectedResult(p *problem.Problem) models.BulkResult {
+	body := p.Members()
+	return models.BulkResult{Status: p.Status, Error: &body}
+}
+
+// updateItem prepares item and stores it as the item with the given id,
+// provided the stored item is at one of versions (nil for any).
+func updateItem(ctx context.Context, id string, versions []int64, item *models.Item) error {
+	if err := prepareItem(ctx, item); err != nil {
+		return err
+	}
+	return db.UpdateItem(ctx, id, versions, item)
+}
+
+// patchItem merges patch into the item with the given id. The merged item
+// goes through the same preparation as a full update, but only the fields
+// the patch mentions are written.
+func patchItem(ctx context.Context, id string, versions []int64, patch models.ItemPatch) (models.Item, error) {
+	if patch.Id.Set {
+		return models.Item{}, problem.New(http.StatusUnprocessableEntity, "id cannot be changed")
+	}
+	if patch.CreatedAt.Set || patch.UpdatedAt.Set || patch.Version.Set {
+		return models.Item{}, problem.New(http.StatusUnprocessableEntity, "created_at, updated_at and version cannot be changed")
+	}
+	if patch.Name.Set && patch.Name.Value == nil {
+		return models.Item{}, problem.New(http.StatusUnprocessableEntity, "name cannot be null")
+	}
+
+	item, err := db.GetItem(ctx, id)
+	if err != nil {
+		return models.Item{}, err
+	}
+	patch.Apply(&item)
+	if patch.Description.Set {
+		if err := fillDescription(&item); err != nil {
+			return models.Item{}, err
+		}
+		patch.Description.Value = item.Description
+	}
+	if err := validateItem(ctx, item); err != nil {
+		return models.Item{}, err
+	}
+	return db.PatchItem(ctx, id, versions, patch)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"sample/db"
+	"sample/db/dbtest"
+	"sample/models"
+)
+
+func namedItems(names ...string) []models.Item {
+	items := make([]models.Item, len(names))
+	for i := range names {
+		items[i].Name = &names[i]
+	}
+	return items
+}
+
+func TestCreateItemsMatchesRowsToItems(t *testing.T) {
+	dbtest.Setup(t)
+	ctx := context.Background()
+
+	names := make([]string, 50)
+	for i := range names {
+		names[i] = fmt.Sprintf("bulk item %d", i)
+	}
+	results, ok, err := createItems(ctx, namedItems(names...))
+	if err != nil || !ok {
+		t.Fatalf("createItems = %v, %v", ok, err)
+	}
+	for i, r := range results {
+		if r.Status != http.StatusCreated || r.Item == nil || *r.Item.Name != names[i] {
+			t.Fatalf("results[%d] = %+v, want item %q created", i, r, names[i])
+		}
+		stored, err := db.GetItem(ctx, *r.Item.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *stored.Name != names[i] || !stored.CreatedAt.Equal(*r.Item.CreatedAt) {
+			t.Errorf("item %s is stored as %q, result says %q", *r.Item.Id, *stored.Name, names[i])
+		}
+	}
+}
+
+func TestCreateItemsReportsRejectedItems(t *testing.T) {
+	dbtest.Setup(t)
+	ctx := context.Background()
+	if _, err := db.DB.ExecContext(ctx, "CREATE UNIQUE INDEX items_name_key ON items (name)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateItem(ctx, &namedItems("taken")[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	results, ok, err := createItems(ctx, namedItems("fresh", "taken", "twice", "twice"))
+	if err != nil || ok {
+		t.Fatalf("createItems = %v, %v; want per-item results", ok, err)
+	}
+	var statuses []int
+	for _, r := range results {
+		statuses = append(statuses, r.Status)
+	}
+	want := []int{http.StatusFailedDependency, http.StatusConflict, http.StatusFailedDependency, http.StatusConflict}
+	if !slices.Equal(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	stored, err := db.ListItems(ctx, db.ItemQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("%d items stored, want only the one created before the bulk call", len(stored))
+	}
+}
+
+func TestListItemsInvalidFilterIsBadRequest(t *testing.T) {
+	for _, expr := range []string{"id==abc", "id=in=(1,2x)", "id=gt=99999999999", "name==a;secret==1"} {
+		_, err := listItems(context.Background(), models.GetItemsParams{Filter: &expr})
+		if p := toProblem(err); p.Status != http.StatusBadRequest {
+			t.Errorf("filter %q: status = %d, want 400 (err %v)", expr, p.Status, err)
+		}
+	}
+}
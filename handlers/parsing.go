@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/problem"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// ParsingMode decides what happens to request input the API does not
+// understand: unknown JSON fields, undeclared query parameters and query
+// values of the wrong type.
+type ParsingMode int
+
+const (
+	// Strict rejects such requests with 400.
+	Strict ParsingMode = iota
+	// Lenient drops the offending input and reports each drop in a
+	// Warning header.
+	Lenient
+)
+
+// ParseParsingMode reads "strict" or "lenient"; empty means Strict.
+func ParseParsingMode(s string) (ParsingMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "strict":
+		return Strict, nil
+	case "lenient":
+		return Lenient, nil
+	}
+	return Strict, fmt.Errorf("unknown parsing mode %q, want strict or lenient", s)
+}
+
+type parsingModeKey struct{}
+
+func parsingMode(ctx context.Context) ParsingMode {
+	mode, _ := ctx.Value(parsingModeKey{}).(ParsingMode)
+	return mode
+}
+
+// specPathParam matches OpenAPI path parameters such as {id}.
+var specPathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// RequestParsing applies mode to every request, unless the client asks for
+// the other one with Prefer: handling=strict or handling=lenient (RFC
+// 7240). Query parameters are checked here against the operations declared
+// in spec; JSON bodies are checked by the handlers when they decode them.
+func RequestParsing(spec *openapi3.T, mode ParsingMode) gin.HandlerFunc {
+	// Declared query parameters by "METHOD /gin/route".
+	queryParams := map[string]map[string]*openapi3.Schema{}
+	for path, item := range spec.Paths.Map() {
+		route := specPathParam.ReplaceAllString(path, ":$1")
+		for method, op := range item.Operations() {
+			declared := map[string]*openapi3.Schema{}
+			for _, params := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+				for _, p := range params {
+					if p.Value.In == openapi3.ParameterInQuery {
+						declared[p.Value.Name] = p.Value.Schema.Value
+					}
+				}
+			}
+			queryParams[method+" "+route] = declared
+		}
+	}
+
+	return func(c *gin.Context) {
+		requestMode := mode
+		if preferred, ok := preferredHandling(c.Request.Header.Values("Prefer")); ok {
+			requestMode = preferred
+			if preferred == Lenient {
+				c.Header("Preference-Applied", "handling=lenient")
+			} else {
+				c.Header("Preference-Applied", "handling=strict")
+			}
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), parsingModeKey{}, requestMode))
+
+		declared, ok := queryParams[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		query := c.Request.URL.Query()
+		var problems []string
+		for name, values := range query {
+			schema, known := declared[name]
+			switch {
+			case !known:
+				problems = append(problems, fmt.Sprintf("unknown query parameter %q", name))
+			case !validQueryValues(schema, values):
+				problems = append(problems, fmt.Sprintf("invalid value for query parameter %q", name))
+			default:
+				continue
+			}
+			query.Del(name)
+		}
+		if len(problems) == 0 {
+			c.Next()
+			return
+		}
+		sort.Strings(problems)
+		if requestMode == Strict {
+			abortWithError(c, problem.New(http.StatusBadRequest, "invalid query parameters").With("details", problems))
+			return
+		}
+		for _, problem := range problems {
+			warn(c, "ignored "+problem)
+		}
+		c.Request.URL.RawQuery = query.Encode()
+		c.Next()
+	}
+}
+
+// preferredHandling finds a handling preference among Prefer headers.
+func preferredHandling(prefer []string) (ParsingMode, bool) {
+	for _, header := range prefer {
+		for _, pref := range strings.Split(header, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(strings.SplitN(pref, ";", 2)[0]), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "handling") {
+				continue
+			}
+			switch strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`)) {
+			case "strict":
+				return Strict, true
+			case "lenient":
+				return Lenient, true
+			}
+		}
+	}
+	return Strict, false
+}
+
+// validQueryValues reports whether values parse as schema's type. Range
+// and other constraints are left to the handlers.
+func validQueryValues(schema *openapi3.Schema, values []string) bool {
+	if schema.Type.Is(openapi3.TypeArray) {
+		var items []string
+		for _, v := range values {
+			items = append(items, strings.Split(v, ",")...)
+		}
+		return validQueryValues(schema.Items.Value, items)
+	}
+	for _, v := range values {
+		var err error
+		switch {
+		case schema.Type.Is(openapi3.TypeInteger):
+			_, err = strconv.ParseInt(v, 10, 64)
+		case schema.Type.Is(openapi3.TypeNumber):
+			_, err = strconv.ParseFloat(v, 64)
+		case schema.Type.Is(openapi3.TypeBoolean):
+			_, err = strconv.ParseBool(v)
+		case schema.Format == "date-time":
+			_, err = time.Parse(time.RFC3339, v)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// bindJSON decodes the request body into dst. An unknown field is a 400 in
+// strict mode; in lenient mode the fields are dropped and the first of
+// them is named in a Warning header.
+func bindJSON(c *gin.Context, dst any) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(dst)
+	if field, ok := unknownField(err); ok && parsingMode(c.Request.Context()) == Lenient {
+		warn(c, fmt.Sprintf("ignored unknown field %q", field))
+		err = json.Unmarshal(body, dst)
+	}
+	if err != nil {
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+// unknownField extracts the field name from the error encoding/json
+// returns under DisallowUnknownFields.
+func unknownField(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	field, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+	name, unquoteErr := strconv.Unquote(field)
+	if unquoteErr != nil {
+		return field, true
+	}
+	return name, true
+}
+
+// warn adds a Warning header (code 299, miscellaneous persistent warning).
+func warn(c *gin.Context, text string) {
+	c.Writer.Header().Add("Warning", "299 - "+strconv.Quote(text))
+}
@@ -0,0 +1,20 @@
+package handlers
+
+// Responses follow one serialization policy across endpoints:
+//
+//   - collections are always JSON arrays, [] when empty and never null;
+//   - optional fields without a value are omitted rather than sent as null,
+//     as the omitempty tags of the generated models do;
+//   - required fields, such as the rank of a search result, are always
+//     present, even when zero.
+//
+// Endpoints returning a list pass it through collection before encoding.
+
+// collection returns items, or an empty slice when items is nil, so the
+// list encodes as [] rather than null.
+func collection[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sample/db/dbtest"
+	"sample/generated"
+	"sample/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	generated.RegisterHandlersWithOptions(router, Server{}, generated.GinServerOptions{
+		ErrorHandler: ParameterError,
+	})
+	return router
+}
+
+func serve(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWriteItemsEmptyIsArray(t *testing.T) {
+	for _, items := range [][]models.Item{nil, {}} {
+		rec := httptest.NewRecorder()
+		if err := writeItems(rec, http.StatusOK, items); err != nil {
+			t.Fatal(err)
+		}
+		if got := rec.Body.String(); got != "[]" {
+			t.Errorf("writeItems(%#v) = %s, want []", items, got)
+		}
+	}
+}
+
+// The list path may use the hand-written encoder (-tags itemjson) while
+// single items go through encoding/json; both must leave unset fields out.
+func TestWriteItemsOmitsUnsetFields(t *testing.T) {
+	name, id := "only a name", "7"
+	items := []models.Item{{Name: &name, Id: &id}, {}}
+
+	rec := httptest.NewRecorder()
+	if err := writeItems(rec, http.StatusOK, items); err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Body.String(); got != string(want) {
+		t.Errorf("writeItems = %s, want %s", got, want)
+	}
+	if strings.Contains(rec.Body.String(), "null") {
+		t.Errorf("writeItems = %s, want unset fields omitted rather than null", rec.Body)
+	}
+}
+
+func TestBulkRejectedResultsAreArray(t *testing.T) {
+	rec := serve(newTestRouter(), http.MethodPost, "/items/bulk", `[{"name": "a", "latitude": 1}, {"name": "b"}]`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422: %s", rec.Code, rec.Body)
+	}
+	var results []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil || len(results) != 2 {
+		t.Fatalf("body = %s, want an array of 2 results (%v)", rec.Body, err)
+	}
+	if results[1]["status"] != float64(http.StatusFailedDependency) {
+		t.Errorf("second result = %v, want status 424", results[1])
+	}
+	if strings.Contains(rec.Body.String(), "null") {
+		t.Errorf("body = %s, want unset fields omitted rather than null", rec.Body)
+	}
+}
+
+func TestEmptyCollectionsAreArrays(t *testing.T) {
+	dbtest.Setup(t)
+	router := newTestRouter()
+
+	for _, target := range []string{
+		"/items",
+		"/items?name=nothing",
+		"/items/nearby?lat=0&lon=0&radius=1000",
+		"/items/search?q=nothing",
+	} {
+		rec := serve(router, http.MethodGet, target, "")
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: status = %d, want 200: %s", target, rec.Code, rec.Body)
+			continue
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != "[]" {
+			t.Errorf("GET %s = %s, want []", target, got)
+		}
+	}
+}
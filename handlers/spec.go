@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"sample/problem"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateAgainstSpec checks every request to an operation of spec against
+// its declaration: required parameters, parameter formats and ranges, and
+// the body schema. Mismatches are rejected with 400 listing each of them,
+// or with 422 when the only ones are values outside an enum. Requests for
+// paths spec does not declare are left to the router.
+//
+// With checkResponses set, responses are checked too and any drift from the
+// spec is logged. That buffers every response body, so it is meant for
+// development and testing rather than production.
+func ValidateAgainstSpec(spec *openapi3.T, checkResponses bool) (gin.HandlerFunc, error) {
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			// Not an operation of spec, such as /batch.
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, problem.New(http.StatusBadRequest, err.Error()))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Validate a copy: the validator consumes the body, and versioned
+		// media types are checked as the plain JSON the spec declares.
+		req := c.Request.Clone(c.Request.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.Header.Set("Content-Type", specMediaType(req.Header.Get("Content-Type")))
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError: true,
+				// Defaults are applied by the handlers, which tell an absent
+				// parameter from one set to its default (ids vs limit).
+				SkipSettingDefaults: true,
+				// Clients may send back an item as they read it; the handlers
+				// ignore its read-only fields.
+				ExcludeReadOnlyValidations: true,
+				// A merge patch uses null to clear a field, so its body is not
+				// an Item; the handler validates the merged result instead.
+				ExcludeRequestBody: mediaType(req.Header.Get("Content-Type")) == "application/merge-patch+json",
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			abortWithError(c, specProblem(err))
+			return
+		}
+
+		if !checkResponses {
+			c.Next()
+			return
+		}
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		header := recorder.Header().Clone()
+		header.Set("Content-Type", specMediaType(header.Get("Content-Type")))
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.Status(),
+			Header:                 header,
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                &openapi3filter.Options{MultiError: true},
+		})
+		if err != nil {
+			log.Printf("%s %s: response does not match the API specification: %v", c.Request.Method, c.Request.URL.Path, specErrors(err))
+		}
+	}, nil
+}
+
+// specMediaType maps the versioned media types negotiated by
+// NegotiateVersion to the application/json the spec declares.
+func specMediaType(contentType string) string {
+	if _, versioned := parseVendorVersion(mediaType(contentType)); versioned {
+		return "application/json"
+	}
+	return contentType
+}
+
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// specProblem reports a failed request validation. A request whose only
+// fault is a value outside an enum is well-formed but unprocessable: it gets
+// 422 with the allowed values of each offending field, so clients can
+// correct it, or learn that they are behind the spec.
+func specProblem(err error) *problem.Problem {
+	details := specErrors(err)
+	allowed := map[string][]any{}
+	if enumViolations("", err, allowed) {
+		return problem.New(http.StatusUnprocessableEntity, "request contains values the API does not accept").
+			With("details", details).
+			With("allowed_values", allowed)
+	}
+	return problem.New(http.StatusBadRequest, "request does not match the API specification").
+		With("details", details)
+}
+
+// enumViolations adds the allowed values of every enum err reports to
+// allowed, keyed like specErrors, and reports whether every error found
+// is one.
+func enumViolations(where string, err error, allowed map[string][]any) bool {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		all := true
+		for _, err := range err {
+			all = enumViolations(where, err, allowed) && all
+		}
+		return all
+	case *openapi3filter.RequestError:
+		where = "request body"
+		if err.Parameter != nil {
+			where = fmt.Sprintf("%s parameter %q", err.Parameter.In, err.Parameter.Name)
+		}
+		return err.Err != nil && enumViolations(where, err.Err, allowed)
+	case *openapi3.SchemaError:
+		if err.SchemaField != "enum" || err.Schema == nil {
+			return false
+		}
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			where += ": /" + strings.Join(pointer, "/")
+		}
+		allowed[where] = err.Schema.Enum
+		return true
+	}
+	return false
+}
+
+// specErrors lists the individual errors of a validation with MultiError
+// set, each as "where: reason" without the schema dump kin-openapi adds.
+func specErrors(err error) []string {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var details []string
+		for _, err := range err {
+			details = append(details, specErrors(err)...)
+		}
+		return details
+	case *openapi3filter.RequestError:
+		where := "request body"
+		if err.Parameter != nil {
+			where = fmt.Sprintf("%s parameter %q", err.Parameter.In, err.Parameter.Name)
+		}
+		return prefixed(where, err.Err, err.Reason)
+	case *openapi3filter.ResponseError:
+		return prefixed("response", err.Err, err.Reason)
+	case *openapi3.SchemaError:
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			return []string{"/" + strings.Join(pointer, "/") + ": " + err.Reason}
+		}
+		return []string{err.Reason}
+	}
+	return []string{err.Error()}
+}
+
+func prefixed(where string, err error, reason string) []string {
+	if err == nil {
+		return []string{where + ": " + reason}
+	}
+	details := specErrors(err)
+	for i := range details {
+		details[i] = where + ": " + details[i]
+	}
+	return details
+}
+
+// responseRecorder keeps a copy of the response body for validation.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
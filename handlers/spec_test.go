@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sample/openapi"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// enumSpec declares enums in a query parameter and a body field; the item
+// API has none of its own yet.
+const enumSpec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "enums", "version": "1"},
+	"paths": {"/things": {
+		"get": {
+			"parameters": [{"in": "query", "name": "order", "schema": {"type": "string", "enum": ["asc", "desc"]}}],
+			"responses": {"204": {"description": "ok"}}
+		},
+		"post": {
+			"requestBody": {"required": true, "content": {"application/json": {"schema": {
+				"type": "object",
+				"properties": {
+					"status": {"type": "string", "enum": ["open", "closed"]},
+					"count": {"type": "integer"}
+				}
+			}}}},
+			"responses": {"204": {"description": "ok"}}
+		}
+	}}
+}`
+
+func newEnumRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(enumSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate, err := ValidateAgainstSpec(spec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(validate)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/things", ok)
+	router.POST("/things", ok)
+	return router
+}
+
+func TestValidateAgainstSpecEnums(t *testing.T) {
+	router := newEnumRouter(t)
+	tests := []struct {
+		name           string
+		method, target string
+		body           string
+		status         int
+		allowed        map[string]any
+	}{
+		{name: "valid query", method: http.MethodGet, target: "/things?order=asc", status: http.StatusNoContent},
+		{name: "valid body", method: http.MethodPost, target: "/things", body: `{"status": "open", "count": 1}`, status: http.StatusNoContent},
+		{
+			name: "unknown query value", method: http.MethodGet, target: "/things?order=sideways",
+			status:  http.StatusUnprocessableEntity,
+			allowed: map[string]any{`query parameter "order"`: []any{"asc", "desc"}},
+		},
+		{
+			name: "unknown body value", method: http.MethodPost, target: "/things", body: `{"status": "archived"}`,
+			status:  http.StatusUnprocessableEntity,
+			allowed: map[string]any{"request body: /status": []any{"open", "closed"}},
+		},
+		{
+			name: "unknown value next to another error", method: http.MethodPost, target: "/things", body: `{"status": "archived", "count": "x"}`,
+			status: http.StatusBadRequest,
+		},
+		{name: "malformed body", method: http.MethodPost, target: "/things", body: `{`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, tt.method, tt.target, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusNoContent {
+				return
+			}
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %s: %v", rec.Body, err)
+			}
+			if details, _ := body["details"].([]any); len(details) == 0 {
+				t.Errorf("body = %s, want details", rec.Body)
+			}
+			allowed, _ := body["allowed_values"].(map[string]any)
+			if tt.allowed == nil && allowed != nil {
+				t.Errorf("allowed_values = %v, want none", allowed)
+			}
+			if tt.allowed != nil && !reflect.DeepEqual(allowed, tt.allowed) {
+				t.Errorf("allowed_values = %v, want %v", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+// TestSpecCapsLimits checks that the spec, not the handlers, enforces
+// MaxPageSize on every limit parameter.
+func TestSpecCapsLimits(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate, err := ValidateAgainstSpec(spec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(validate)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+	var targets []string
+	for path, item := range spec.Paths.Map() {
+		if item.Get == nil {
+			continue
+		}
+		for _, param := range item.Get.Parameters {
+			if param.Value.Name != "limit" {
+				continue
+			}
+			if limit := param.Value.Schema.Value.Max; limit == nil || *limit != MaxPageSize {
+				t.Errorf("GET %s: limit maximum is not %d", path, MaxPageSize)
+			}
+			router.GET(path, ok)
+			targets = append(targets, path)
+		}
+	}
+	if len(targets) == 0 {
+		t.Fatal("no operation takes a limit parameter")
+	}
+	for _, path := range targets {
+		// Fill in the other query parameters the operation requires.
+		target := path + "?q=x&lat=0&lon=0&radius=1&limit="
+		if rec := serve(router, http.MethodGet, target+"200", ""); rec.Code != http.StatusNoContent {
+			t.Errorf("GET %s200: status = %d, want 204: %s", target, rec.Code, rec.Body)
+		}
+		if rec := serve(router, http.MethodGet, target+"201", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s201: status = %d, want 400: %s", target, rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sample/models"
+	"sample/problem"
+	"sync"
+)
+
+// ItemValidator checks an item before it is created or updated, returning
+// an error that describes why the item is not acceptable. Validators may
+// consult external systems and should honour ctx.
+type ItemValidator func(ctx context.Context, item models.Item) error
+
+var (
+	validatorsMu   sync.RWMutex
+	itemValidators []ItemValidator
+)
+
+// RegisterItemValidator adds v to the validators run on every item write.
+// Deployments register their own rules, such as naming conventions or
+// catalog lookups, before the server starts.
+func RegisterItemValidator(v ItemValidator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	itemValidators = append(itemValidators, v)
+}
+
+// validateItem checks the item's coordinates, runs every registered
+// validator and collects all failures into a single 422 error, so clients
+// see every problem at once rather than one per request.
+func validateItem(ctx context.Context, item models.Item) error {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+
+	var problems []string
+	switch {
+	case (item.Latitude == nil) != (item.Longitude == nil):
+		problems = append(problems, "latitude and longitude must be set together")
+	case item.Latitude != nil:
+		if err := validateCoordinates(*item.Latitude, *item.Longitude); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, v := range itemValidators {
+		if err := v(ctx, item); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return problem.New(http.StatusUnprocessableEntity, "item failed validation").With("details", problems)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sample/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LatestAPIVersion is the representation served when the client does not
+// ask for a specific one. Bump it, and add the old number to
+// SupportedAPIVersions, when a representation changes incompatibly.
+const LatestAPIVersion = 1
+
+// SupportedAPIVersions lists the representation versions still served.
+var SupportedAPIVersions = []int{1}
+
+// vendorMediaType matches versioned media types such as
+// application/vnd.items.v2+json.
+var vendorMediaType = regexp.MustCompile(`^application/vnd\.items\.v([0-9]+)\+json$`)
+
+// NegotiateVersion picks the representation version of each request from
+// its media types, so representations can change without new URL prefixes.
+// Clients opt in with Accept: application/vnd.items.vN+json, and the reply
+// then carries the same media type. A versioned Content-Type on the request
+// body has to name a supported version too. Plain application/json gets
+// LatestAPIVersion.
+func NegotiateVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept")
+
+		if version, versioned, ok := bodyVersion(c.GetHeader("Content-Type")); versioned && !ok {
+			abortWithError(c, problem.Newf(http.StatusUnsupportedMediaType, "unsupported media type version %d", version))
+			return
+		}
+
+		version, versioned, ok := acceptedVersion(c.GetHeader("Accept"))
+		if !ok {
+			abortWithError(c, problem.New(http.StatusNotAcceptable, "none of the requested media type versions is supported").
+				With("supported_versions", SupportedAPIVersions))
+			return
+		}
+		if versioned {
+			c.Header("Content-Type", fmt.Sprintf("application/vnd.items.v%d+json; charset=utf-8", version))
+		}
+		c.Next()
+	}
+}
+
+// bodyVersion reads the version of a request body's media type.
+func bodyVersion(contentType string) (version int, versioned, ok bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0, false, true
+	}
+	version, versioned = parseVendorVersion(mediaType)
+	return version, versioned, !versioned || supportedVersion(version)
+}
+
+// acceptedVersion picks the highest supported version among the vendor
+// media types in accept. Without any vendor type it returns
+// LatestAPIVersion unversioned; when only unsupported ones are listed, ok
+// is false unless the client also accepts plain JSON.
+func acceptedVersion(accept string) (version int, versioned, ok bool) {
+	plain := accept == ""
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || refused(params) {
+			continue
+		}
+		if v, isVendor := parseVendorVersion(mediaType); isVendor {
+			if supportedVersion(v) && v > version {
+				version = v
+			}
+			continue
+		}
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			plain = true
+		}
+	}
+	if version > 0 {
+		return version, true, true
+	}
+	return LatestAPIVersion, false, plain || !strings.Contains(accept, "application/vnd.items.")
+}
+
+// refused reports whether the q parameter of an Accept entry rules the
+// media type out. Any spelling of zero, such as q=0.000, does.
+func refused(params map[string]string) bool {
+	q, ok := params["q"]
+	if !ok {
+		return false
+	}
+	weight, err := strconv.ParseFloat(q, 64)
+	return err == nil && weight == 0
+}
+
+func parseVendorVersion(mediaType string) (int, bool) {
+	m := vendorMediaType.FindStringSubmatch(strings.ToLower(mediaType))
+	if m == nil {
+		return 0, false
+	}
+	v, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func supportedVersion(v int) bool {
+	return slices.Contains(SupportedAPIVersions, v)
+}
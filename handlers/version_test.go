@@ -0,0 +1,56 @@
+package handlers
+
+import "testing"
+
+func TestAcceptedVersion(t *testing.T) {
+	supported := SupportedAPIVersions
+	SupportedAPIVersions = []int{1, 2}
+	t.Cleanup(func() { SupportedAPIVersions = supported })
+
+	tests := []struct {
+		accept    string
+		version   int
+		versioned bool
+		ok        bool
+	}{
+		{"", LatestAPIVersion, false, true},
+		{"application/json", LatestAPIVersion, false, true},
+		{"*/*", LatestAPIVersion, false, true},
+		{"application/vnd.items.v1+json", 1, true, true},
+		{"application/vnd.items.v1+json, application/vnd.items.v2+json", 2, true, true},
+		{"application/vnd.items.v2+json; q=0.5", 2, true, true},
+		{"application/vnd.items.v9+json", LatestAPIVersion, false, false},
+		{"application/vnd.items.v9+json, application/json", LatestAPIVersion, false, true},
+		{"application/vnd.items.v2+json; q=0, application/vnd.items.v1+json", 1, true, true},
+		{"application/vnd.items.v2+json; q=0.0, application/vnd.items.v1+json", 1, true, true},
+		{"application/vnd.items.v2+json; q=0.000, application/vnd.items.v1+json", 1, true, true},
+		{"application/vnd.items.v1+json; q=0.000", LatestAPIVersion, false, false},
+		{"application/vnd.items.v9+json, application/json; q=0.0", LatestAPIVersion, false, false},
+	}
+	for _, tt := range tests {
+		version, versioned, ok := acceptedVersion(tt.accept)
+		if version != tt.version || versioned != tt.versioned || ok != tt.ok {
+			t.Errorf("acceptedVersion(%q) = %d, %t, %t, want %d, %t, %t",
+				tt.accept, version, versioned, ok, tt.version, tt.versioned, tt.ok)
+		}
+	}
+}
+
+func TestBodyVersion(t *testing.T) {
+	tests := []struct {
+		contentType string
+		versioned   bool
+		ok          bool
+	}{
+		{"", false, true},
+		{"application/json", false, true},
+		{"application/vnd.items.v1+json; charset=utf-8", true, true},
+		{"application/vnd.items.v9+json", true, false},
+	}
+	for _, tt := range tests {
+		_, versioned, ok := bodyVersion(tt.contentType)
+		if versioned != tt.versioned || ok != tt.ok {
+			t.Errorf("bodyVersion(%q) = %t, %t, want %t, %t", tt.contentType, versioned, ok, tt.versioned, tt.ok)
+		}
+	}
+}
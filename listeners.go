@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"sample/config"
+	"sample/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listener is one of the HTTP servers the process runs. Each has its own
+// router, and so its own middleware stack, and its own TLS settings.
+type listener struct {
+	name     string
+	addr     string
+	certFile string
+	keyFile  string
+	handler  http.Handler
+}
+
+func newListener(name string, cfg config.Listener, handler http.Handler) listener {
+	return listener{
+		name:     name,
+		addr:     cfg.Addr,
+		certFile: cfg.TLSCertFile,
+		keyFile:  cfg.TLSKeyFile,
+		handler:  handler,
+	}
+}
+
+func (l listener) serve() error {
+	server := &http.Server{Addr: l.addr, Handler: l.handler}
+	if l.certFile != "" {
+		log.Printf("%s listener on %s (TLS)", l.name, l.addr)
+		return server.ListenAndServeTLS(l.certFile, l.keyFile)
+	}
+	log.Printf("%s listener on %s", l.name, l.addr)
+	return server.ListenAndServe()
+}
+
+// serveAll runs the listeners with a non-empty address and returns when the
+// first of them fails.
+func serveAll(listeners ...listener) error {
+	errs := make(chan error, len(listeners))
+	running := 0
+	for _, l := range listeners {
+		if l.addr == "" {
+			log.Printf("%s listener disabled", l.name)
+			continue
+		}
+		running++
+		go func() { errs <- l.serve() }()
+	}
+	if running == 0 {
+		return errors.New("every listener is disabled")
+	}
+	return <-errs
+}
+
+// adminRouter serves the internal endpoints: health checks for the
+// orchestrator and pprof. It must not be exposed publicly.
+func adminRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/healthz", handlers.Healthz)
+	router.GET("/readyz", handlers.Readyz)
+
+	debug := router.Group("/debug/pprof")
+	debug.GET("/", gin.WrapF(pprof.Index))
+	debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/profile", gin.WrapF(pprof.Profile))
+	debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+	debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/trace", gin.WrapF(pprof.Trace))
+	debug.GET("/:profile", gin.WrapF(pprof.Index))
+	return router
+}
@@ -2,21 +2,88 @@ package main
 
 import (
 	"log"
+	"sample/config"
 	"sample/db"
+	"sample/generated"
 	"sample/handlers"
+	"sample/openapi"
+	"text/template"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	db.Connect()
+	configureRuntime()
+	// Settings come from the defaults, the YAML file named by CONFIG_FILE
+	// and the environment, in increasing order of precedence; see
+	// config.example.yaml.
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if cfg.LogLevel == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	if err := db.SetHighlightOptions(db.Highlight(cfg.Search.Highlight)); err != nil {
+		log.Fatalf("Invalid search highlight settings: %v", err)
+	}
+	db.Connect(cfg.Database)
+
+	if text := cfg.ItemDescriptionTemplate; text != "" {
+		tmpl, err := template.New("description").Parse(text)
+		if err != nil {
+			log.Fatalf("Invalid item_description_template: %v", err)
+		}
+		handlers.DescriptionTemplate = tmpl
+	}
+
+	// The served spec is openapi.yaml merged with the per-resource fragments
+	// in openapi/resources, the same document the routes are generated from.
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+	// The lenient parsing mode drops unknown fields and parameters with a
+	// Warning header instead of rejecting the request; clients can choose
+	// per request with Prefer: handling=strict|lenient.
+	parsingMode, err := handlers.ParseParsingMode(cfg.RequestParsingMode)
+	if err != nil {
+		log.Fatalf("Invalid request_parsing_mode: %v", err)
+	}
 
 	router := gin.Default()
+	router.Use(handlers.NegotiateVersion())
+	router.Use(handlers.RequestParsing(spec, parsingMode))
+	validate, err := handlers.ValidateAgainstSpec(spec, cfg.ValidateResponses)
+	if err != nil {
+		log.Fatalf("Failed to build spec validation: %v", err)
+	}
+	router.Use(validate)
+	if budget := cfg.Database.StatementBudget; budget > 0 {
+		router.Use(handlers.StatementBudget(budget, cfg.Database.EnforceStatementBudget))
+	}
+
+	generated.RegisterHandlersWithOptions(router, handlers.Server{}, generated.GinServerOptions{
+		ErrorHandler: handlers.ParameterError,
+	})
 
-	router.GET("/items", handlers.GetItems)
-	router.POST("/items", handlers.CreateItem)
+	router.POST("/batch", handlers.Batch(router))
 
-	// Add routes for other handlers here
+	document, err := handlers.OpenAPIDocument(spec)
+	if err != nil {
+		log.Fatalf("Failed to encode OpenAPI spec: %v", err)
+	}
+	router.GET("/openapi.json", document)
+	router.GET("/docs", handlers.Docs)
+	router.NoRoute(handlers.RouteNotFound)
 
-	log.Fatal(router.Run(":8080"))
+	// http configures the public item API, admin the internal listener; an
+	// empty address turns either off.
+	log.Fatal(serveAll(
+		newListener("public", cfg.HTTP, router),
+		newListener("admin", cfg.Admin, adminRouter()),
+	))
 }
@@ -0,0 +1,4 @@
+package models
+
+//go:generate go run ../cmd/spec -o ../openapi/build/openapi.yaml
+//go:generate oapi-codegen -config ../openapi/codegen/models.yaml ../openapi/build/openapi.yaml
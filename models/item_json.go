@@ -0,0 +1,174 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// AppendItemsJSON appends the JSON encoding of items to dst. The output is
+// byte-for-byte what encoding/json produces for the same slice, including a
+// nil slice encoding as null.
+func AppendItemsJSON(dst []byte, items []Item) []byte {
+	if items == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '[')
+	for i, item := range items {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = item.AppendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+// AppendJSON appends the JSON encoding of the item to dst without going
+// through reflection. Fields are written in declaration order and omitted
+// when nil, matching their json tags; keep it in step with the generated
+// Item struct.
+func (i Item) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	n := len(dst)
+	dst = appendTimeField(dst, n, "created_at", i.CreatedAt)
+	dst = appendStringField(dst, n, "description", i.Description)
+	dst = appendStringField(dst, n, "id", i.Id)
+	dst = appendFloatField(dst, n, "latitude", i.Latitude)
+	dst = appendFloatField(dst, n, "longitude", i.Longitude)
+	dst = appendStringField(dst, n, "name", i.Name)
+	dst = appendTimeField(dst, n, "updated_at", i.UpdatedAt)
+	dst = appendIntField(dst, n, "version", i.Version)
+	return append(dst, '}')
+}
+
+func appendStringField(dst []byte, start int, name string, value *string) []byte {
+	if value == nil {
+		return dst
+	}
+	if len(dst) > start {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+	dst = append(dst, '"', ':')
+	return appendJSONString(dst, *value)
+}
+
+func appendFloatField(dst []byte, start int, name string, value *float64) []byte {
+	if value == nil {
+		return dst
+	}
+	if len(dst) > start {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+	dst = append(dst, '"', ':')
+	return appendJSONFloat(dst, *value)
+}
+
+func appendIntField(dst []byte, start int, name string, value *int64) []byte {
+	if value == nil {
+		return dst
+	}
+	if len(dst) > start {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+	dst = append(dst, '"', ':')
+	return strconv.AppendInt(dst, *value, 10)
+}
+
+// appendTimeField writes value as time.Time.MarshalJSON does, in RFC 3339
+// with nanoseconds. Timestamps come from Postgres, so the year is always
+// within the range MarshalJSON accepts.
+func appendTimeField(dst []byte, start int, name string, value *time.Time) []byte {
+	if value == nil {
+		return dst
+	}
+	if len(dst) > start {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+	dst = append(dst, '"', ':', '"')
+	dst = value.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+// appendJSONFloat formats f the way encoding/json does: like ES6, using
+// exponent notation only for very small or large magnitudes, with a
+// minimal two-digit exponent. Coordinates are always finite.
+func appendJSONFloat(dst []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString quotes s the way encoding/json does with HTML escaping
+// enabled: <, > and & are escaped, as are U+2028 and U+2029, and invalid
+// UTF-8 is replaced with U+FFFD.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// testItems covers what appendJSONString and appendJSONFloat special-case,
+// plus items with every optional field left out.
+func testItems() []Item {
+	strings := []string{
+		"",
+		"plain",
+		`quote " and backslash \`,
+		"<script>&amp;</script>",
+		"line\u2028para\u2029end",
+		"bad utf-8: \xff\xfe \xc3 end",
+		"truncated rune \xe2\x82",
+		"multibyte é 世界 🙂",
+		"\x7f delete",
+	}
+	for b := 0; b < 0x20; b++ {
+		strings = append(strings, fmt.Sprintf("control %c char", b))
+	}
+	floats := []float64{
+		0, math.Copysign(0, -1), 1, -1, 0.1, 45.123456789, -179.999999,
+		1e-6, 9.99e-7, 1e-7, 123456789e-15, 1e20, 1e21, -1e21, 1.5e300, math.SmallestNonzeroFloat64,
+	}
+	created := time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.UTC)
+	updated := time.Date(2024, 3, 2, 0, 0, 0, 0, time.FixedZone("", 2*3600))
+
+	items := []Item{{}}
+	for i, s := range strings {
+		items = append(items, Item{Name: ptr(s), Description: ptr(s), Id: ptr(fmt.Sprint(i))})
+	}
+	for i, f := range floats {
+		items = append(items, Item{Latitude: ptr(f), Longitude: ptr(-f), Version: ptr(int64(i) - 1)})
+	}
+	return append(items, Item{
+		CreatedAt:   &created,
+		Description: ptr("full"),
+		Id:          ptr("6f1c2a8e-8c4b-4f4e-9a0e-2f6b7b0d9c11"),
+		Latitude:    ptr(52.52),
+		Longitude:   ptr(13.405),
+		Name:        ptr("every field"),
+		UpdatedAt:   &updated,
+		Version:     ptr(int64(math.MaxInt64)),
+	})
+}
+
+func TestAppendItemsJSONMatchesEncodingJSON(t *testing.T) {
+	all := testItems()
+	for _, items := range [][]Item{nil, {}, all} {
+		want, err := json.Marshal(items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := AppendItemsJSON(nil, items); string(got) != string(want) {
+			t.Errorf("AppendItemsJSON(%d items) =\n%s\nwant\n%s", len(items), got, want)
+		}
+	}
+	for _, item := range all {
+		want, err := json.Marshal(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := item.AppendJSON(nil); string(got) != string(want) {
+			t.Errorf("AppendJSON = %s, want %s", got, want)
+		}
+	}
+}
+
+func benchmarkItems() []Item {
+	created := time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.UTC)
+	items := make([]Item, 200)
+	for i := range items {
+		items[i] = Item{
+			CreatedAt:   &created,
+			Description: ptr(fmt.Sprintf("Item number %d, with a description of typical length & one escape.", i)),
+			Id:          ptr(fmt.Sprint(i + 1)),
+			Latitude:    ptr(52.52 + float64(i)/1000),
+			Longitude:   ptr(13.405 - float64(i)/1000),
+			Name:        ptr(fmt.Sprintf("item-%d", i)),
+			UpdatedAt:   &created,
+			Version:     ptr(int64(i % 7)),
+		}
+	}
+	return items
+}
+
+func BenchmarkAppendItemsJSON(b *testing.B) {
+	items := benchmarkItems()
+	var buf []byte
+	b.ReportAllocs()
+	for range b.N {
+		buf = AppendItemsJSON(buf[:0], items)
+	}
+	b.SetBytes(int64(len(buf)))
+}
+
+func BenchmarkEncodingJSON(b *testing.B) {
+	items := benchmarkItems()
+	var size int
+	b.ReportAllocs()
+	for range b.N {
+		buf, err := json.Marshal(items)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(buf)
+	}
+	b.SetBytes(int64(size))
+}
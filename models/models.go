@@ -1,17 +1,298 @@
-// Package main provides primitives to interact with the openapi HTTP API.
+// Package models provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
+// Code generated by github.com/deepmap/oapi-codegen version v1.16.2 DO NOT EDIT.
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BulkResult defines model for BulkResult.
+type BulkResult struct {
+	// Error Problem details the item would have received from POST /items.
+	Error *map[string]interface{} `json:"error,omitempty"`
+	Item  *Item                   `json:"item,omitempty"`
+
+	// Status Status the item would have received from POST /items.
+	Status int `json:"status"`
+}
+
 // Item defines model for Item.
 type Item struct {
-	Description *string `json:"description,omitempty"`
-	Id          *string `json:"id,omitempty"`
-	Name        *string `json:"name,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Description *string    `json:"description,omitempty"`
+
+	// Id Assigned by the server: a sequential integer, or a UUID on deployments configured with UUID keys.
+	Id *string `json:"id,omitempty"`
+
+	// Latitude Latitude in degrees; set together with longitude.
+	Latitude *float64 `json:"latitude,omitempty"`
+
+	// Longitude Longitude in degrees; set together with latitude.
+	Longitude *float64   `json:"longitude,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Version Incremented on every change; the item's ETag.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// NearbyResult defines model for NearbyResult.
+type NearbyResult struct {
+	// Distance Distance from the requested point in metres.
+	Distance float64 `json:"distance"`
+	Item     Item    `json:"item"`
+}
+
+// Problem Problem details (RFC 7807), sent as application/problem+json by every error response. Some problems carry extra members, such as details listing validation failures.
+type Problem struct {
+	Detail *string `json:"detail,omitempty"`
+
+	// Instance Path of the request that failed.
+	Instance *string `json:"instance,omitempty"`
+	Status   int     `json:"status"`
+	Title    string  `json:"title"`
+
+	// Type URI identifying the kind of problem; about:blank when the status says it all.
+	Type                 string                 `json:"type"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	// Headline Matched text with the search terms highlighted.
+	Headline string `json:"headline"`
+	Item     Item   `json:"item"`
+
+	// Rank Relevance score; 0 when the search index is unavailable.
+	Rank float32 `json:"rank"`
+}
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// PreconditionFailed Problem details (RFC 7807), sent as application/problem+json by every error response. Some problems carry extra members, such as details listing validation failures.
+type PreconditionFailed = Problem
+
+// PreconditionRequired Problem details (RFC 7807), sent as application/problem+json by every error response. Some problems carry extra members, such as details listing validation failures.
+type PreconditionRequired = Problem
+
+// GetItemsParams defines parameters for GetItems.
+type GetItemsParams struct {
+	// Filter RSQL filter expression, e.g. `name==foo*;id=gt=10`. Supported fields are id, name and description; `;` is AND, `,` is OR.
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Name Only return items with exactly this name.
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// DescriptionContains Only return items whose description contains this text (case-insensitive).
+	DescriptionContains *string `form:"description_contains,omitempty" json:"description_contains,omitempty"`
+
+	// Sort Comma-separated sort fields, prefixed with - for descending order, e.g. `-name,id`. Sortable fields are id and name. Defaults to id.
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Maximum number of items to return (default 50, at most 200).
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of items to skip, in id order.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Cursor Opt into cursor (keyset) pagination. Pass an empty value for the first page, then the X-Next-Cursor value of the previous page. Cannot be combined with offset; X-Total-Count is not returned.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// CreatedAfter Only return items created after this time.
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Only return items created before this time.
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Ids Fetch these items, e.g. `ids=3,1,2`, returned in the requested order; unknown ids are skipped. At most 200 ids. Can be combined with the filters but not with sort, limit, offset or cursor, and X-Total-Count is not returned.
+	Ids *[]string `form:"ids,omitempty" json:"ids,omitempty"`
+}
+
+// PostItemsBulkJSONBody defines parameters for PostItemsBulk.
+type PostItemsBulkJSONBody = []Item
+
+// GetItemsNearbyParams defines parameters for GetItemsNearby.
+type GetItemsNearbyParams struct {
+	Lat float64 `form:"lat" json:"lat"`
+	Lon float64 `form:"lon" json:"lon"`
+
+	// Radius Search radius in metres.
+	Radius float64 `form:"radius" json:"radius"`
+
+	// Limit Maximum number of results to return (default 50, at most 200).
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetItemsSearchParams defines parameters for GetItemsSearch.
+type GetItemsSearchParams struct {
+	// Q Search text in web search syntax (quoted phrases, OR, -term).
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of results to return (default 50, at most 200).
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// DeleteItemsIdParams defines parameters for DeleteItemsId.
+type DeleteItemsIdParams struct {
+	// IfMatch ETag of the version the change is based on, as returned by GET, or `*` to apply it to any version. Writes without it are refused with 428.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetItemsIdParams defines parameters for GetItemsId.
+type GetItemsIdParams struct {
+	// IfNoneMatch ETags the client already has; a match returns 304.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+
+	// IfModifiedSince HTTP date; returns 304 if the item has not changed since. Ignored when If-None-Match is sent.
+	IfModifiedSince *string `json:"If-Modified-Since,omitempty"`
+}
+
+// PatchItemsIdParams defines parameters for PatchItemsId.
+type PatchItemsIdParams struct {
+	// IfMatch ETag of the version the change is based on, as returned by GET, or `*` to apply it to any version. Writes without it are refused with 428.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PutItemsIdParams defines parameters for PutItemsId.
+type PutItemsIdParams struct {
+	// IfMatch ETag of the version the change is based on, as returned by GET, or `*` to apply it to any version. Writes without it are refused with 428.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
 // PostItemsJSONRequestBody defines body for PostItems for application/json ContentType.
 type PostItemsJSONRequestBody = Item
 
+// PostItemsBulkJSONRequestBody defines body for PostItemsBulk for application/json ContentType.
+type PostItemsBulkJSONRequestBody = PostItemsBulkJSONBody
+
+// PatchItemsIdApplicationMergePatchPlusJSONRequestBody defines body for PatchItemsId for application/merge-patch+json ContentType.
+type PatchItemsIdApplicationMergePatchPlusJSONRequestBody = Item
+
 // PutItemsIdJSONRequestBody defines body for PutItemsId for application/json ContentType.
 type PutItemsIdJSONRequestBody = Item
+
+// Getter for additional properties for Problem. Returns the specified
+// element and whether it was found
+func (a Problem) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Problem
+func (a *Problem) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Problem to handle AdditionalProperties
+func (a *Problem) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["detail"]; found {
+		err = json.Unmarshal(raw, &a.Detail)
+		if err != nil {
+			return fmt.Errorf("error reading 'detail': %w", err)
+		}
+		delete(object, "detail")
+	}
+
+	if raw, found := object["instance"]; found {
+		err = json.Unmarshal(raw, &a.Instance)
+		if err != nil {
+			return fmt.Errorf("error reading 'instance': %w", err)
+		}
+		delete(object, "instance")
+	}
+
+	if raw, found := object["status"]; found {
+		err = json.Unmarshal(raw, &a.Status)
+		if err != nil {
+			return fmt.Errorf("error reading 'status': %w", err)
+		}
+		delete(object, "status")
+	}
+
+	if raw, found := object["title"]; found {
+		err = json.Unmarshal(raw, &a.Title)
+		if err != nil {
+			return fmt.Errorf("error reading 'title': %w", err)
+		}
+		delete(object, "title")
+	}
+
+	if raw, found := object["type"]; found {
+		err = json.Unmarshal(raw, &a.Type)
+		if err != nil {
+			return fmt.Errorf("error reading 'type': %w", err)
+		}
+		delete(object, "type")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Problem to handle AdditionalProperties
+func (a Problem) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Detail != nil {
+		object["detail"], err = json.Marshal(a.Detail)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'detail': %w", err)
+		}
+	}
+
+	if a.Instance != nil {
+		object["instance"], err = json.Marshal(a.Instance)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'instance': %w", err)
+		}
+	}
+
+	object["status"], err = json.Marshal(a.Status)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'status': %w", err)
+	}
+
+	object["title"], err = json.Marshal(a.Title)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'title': %w", err)
+	}
+
+	object["type"], err = json.Marshal(a.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'type': %w", err)
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Optional is a JSON field that tells a missing key apart from an explicit
+// null. Set reports whether the key was present; Value is nil for null.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON is only called for keys present in the document, which is
+// what marks the field as set.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		o.Value = nil
+		return nil
+	}
+	o.Value = new(T)
+	return json.Unmarshal(data, o.Value)
+}
+
+// ItemPatch is a JSON merge patch (RFC 7396) for an Item: absent fields are
+// left alone and null fields are cleared. Keep it in step with the
+// generated Item struct.
+type ItemPatch struct {
+	CreatedAt   Optional[time.Time] `json:"created_at"`
+	Description Optional[string]    `json:"description"`
+	Id          Optional[string]    `json:"id"`
+	Latitude    Optional[float64]   `json:"latitude"`
+	Longitude   Optional[float64]   `json:"longitude"`
+	Name        Optional[string]    `json:"name"`
+	UpdatedAt   Optional[time.Time] `json:"updated_at"`
+	Version     Optional[int64]     `json:"version"`
+}
+
+// Apply merges the patch into item.
+func (p ItemPatch) Apply(item *Item) {
+	if p.CreatedAt.Set {
+		item.CreatedAt = p.CreatedAt.Value
+	}
+	if p.Description.Set {
+		item.Description = p.Description.Value
+	}
+	if p.Id.Set {
+		item.Id = p.Id.Value
+	}
+	if p.Latitude.Set {
+		item.Latitude = p.Latitude.Value
+	}
+	if p.Longitude.Set {
+		item.Longitude = p.Longitude.Value
+	}
+	if p.Name.Set {
+		item.Name = p.Name.Value
+	}
+	if p.UpdatedAt.Set {
+		item.UpdatedAt = p.UpdatedAt.Value
+	}
+	if p.Version.Set {
+		item.Version = p.Version.Value
+	}
+}
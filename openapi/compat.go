@@ -0,0 +1,234 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Breaking lists the changes from released to current that can break
+// existing clients: removed operations, parameters and fields, changed or
+// narrowed types, values or ranges a request may no longer use, newly
+// required parameters and fields, and values a response may now carry that
+// clients have not seen, such as null or a new enum value. Additions that
+// clients can ignore are not reported. Each entry names the operation and
+// the location it concerns.
+func Breaking(released, current *openapi3.T) []string {
+	c := compatCheck{walking: map[[2]*openapi3.Schema]bool{}}
+	for _, path := range sortedKeys(released.Paths.Map()) {
+		oldItem, newItem := released.Paths.Value(path), current.Paths.Value(path)
+		operations := oldItem.Operations()
+		for _, method := range sortedKeys(operations) {
+			oldOp, where := operations[method], method+" "+path
+			var newOp *openapi3.Operation
+			if newItem != nil {
+				newOp = newItem.GetOperation(method)
+			}
+			if newOp == nil {
+				c.report(where, "operation removed")
+				continue
+			}
+			c.parameters(where, slices.Concat(oldItem.Parameters, oldOp.Parameters), slices.Concat(newItem.Parameters, newOp.Parameters))
+			c.requestBody(where, oldOp.RequestBody, newOp.RequestBody)
+			c.responses(where, oldOp.Responses, newOp.Responses)
+		}
+	}
+	sort.Strings(c.changes)
+	return c.changes
+}
+
+type compatCheck struct {
+	changes []string
+	// walking holds the schema pairs being compared, so that recursive
+	// schemas terminate.
+	walking map[[2]*openapi3.Schema]bool
+}
+
+func (c *compatCheck) report(where, format string, args ...any) {
+	c.changes = append(c.changes, where+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *compatCheck) parameters(where string, released, current openapi3.Parameters) {
+	for _, ref := range current {
+		p := ref.Value
+		old := released.GetByInAndName(p.In, p.Name)
+		if p.Required && (old == nil || !old.Required) {
+			c.report(where, "%s parameter %q is now required", p.In, p.Name)
+		}
+	}
+	for _, ref := range released {
+		old := ref.Value
+		p := current.GetByInAndName(old.In, old.Name)
+		if p == nil {
+			c.report(where, "%s parameter %q removed", old.In, old.Name)
+			continue
+		}
+		if old.Schema != nil && p.Schema != nil {
+			c.schema(fmt.Sprintf("%s (%s parameter %q)", where, old.In, old.Name), "", old.Schema.Value, p.Schema.Value, true)
+		}
+	}
+}
+
+func (c *compatCheck) requestBody(where string, released, current *openapi3.RequestBodyRef) {
+	if released == nil || current == nil {
+		if current != nil && current.Value.Required {
+			c.report(where, "request body is now required")
+		}
+		return
+	}
+	for _, mediaType := range sortedKeys(released.Value.Content) {
+		old, media := released.Value.Content[mediaType], current.Value.Content.Get(mediaType)
+		if media == nil {
+			c.report(where, "request body no longer accepts %s", mediaType)
+			continue
+		}
+		if old.Schema != nil && media.Schema != nil {
+			c.schema(where+" (request body)", "", old.Schema.Value, media.Schema.Value, true)
+		}
+	}
+}
+
+func (c *compatCheck) responses(where string, released, current *openapi3.Responses) {
+	for _, status := range sortedKeys(released.Map()) {
+		old, response := released.Value(status), current.Value(status)
+		if response == nil {
+			c.report(where, "response %s removed", status)
+			continue
+		}
+		for _, mediaType := range sortedKeys(old.Value.Content) {
+			oldMedia, media := old.Value.Content[mediaType], response.Value.Content.Get(mediaType)
+			if media == nil {
+				c.report(where, "response %s no longer returns %s", status, mediaType)
+				continue
+			}
+			if oldMedia.Schema != nil && media.Schema != nil {
+				c.schema(fmt.Sprintf("%s (response %s)", where, status), "", oldMedia.Schema.Value, media.Schema.Value, false)
+			}
+		}
+	}
+}
+
+// schema compares a schema clients send (request) or receive. field is the
+// JSON path within the body, empty for the whole value.
+func (c *compatCheck) schema(where, field string, old, current *openapi3.Schema, request bool) {
+	pair := [2]*openapi3.Schema{old, current}
+	if old == nil || current == nil || c.walking[pair] {
+		return
+	}
+	c.walking[pair] = true
+	defer delete(c.walking, pair)
+	at := where
+	if field != "" {
+		at = where + " " + field
+	}
+
+	if !slices.Equal(old.Type.Slice(), current.Type.Slice()) {
+		c.report(at, "type changed from %s to %s", typeName(old), typeName(current))
+		return
+	}
+	if old.Format != current.Format {
+		c.report(at, "format changed from %q to %q", old.Format, current.Format)
+	}
+	// Clients send what the released schema allowed and read only what it
+	// promised, so nullability breaks them in opposite directions.
+	if request && old.Nullable && !current.Nullable {
+		c.report(at, "no longer nullable")
+	}
+	if !request && !old.Nullable && current.Nullable {
+		c.report(at, "now nullable")
+	}
+	if request {
+		c.narrowed(at, old, current)
+	} else {
+		c.widened(at, old, current)
+	}
+
+	for _, name := range sortedKeys(old.Properties) {
+		oldProp, prop := old.Properties[name], current.Properties[name]
+		if prop == nil {
+			c.report(where, "field %s removed", field+"/"+name)
+			continue
+		}
+		c.schema(where, field+"/"+name, oldProp.Value, prop.Value, request)
+	}
+	if request {
+		for _, name := range current.Required {
+			if !slices.Contains(old.Required, name) {
+				c.report(where, "field %s is now required", field+"/"+name)
+			}
+		}
+	} else {
+		for _, name := range old.Required {
+			if !slices.Contains(current.Required, name) {
+				c.report(where, "field %s is no longer always present", field+"/"+name)
+			}
+		}
+	}
+	if old.Items != nil && current.Items != nil {
+		c.schema(where, field+"/[]", old.Items.Value, current.Items.Value, request)
+	}
+}
+
+// narrowed reports request constraints that reject values the released
+// schema accepted.
+func (c *compatCheck) narrowed(at string, old, current *openapi3.Schema) {
+	for _, value := range old.Enum {
+		if len(current.Enum) > 0 && !slices.ContainsFunc(current.Enum, func(v any) bool { return fmt.Sprint(v) == fmt.Sprint(value) }) {
+			c.report(at, "value %v no longer accepted", value)
+		}
+	}
+	if len(old.Enum) == 0 && len(current.Enum) > 0 {
+		c.report(at, "now restricted to %d values", len(current.Enum))
+	}
+	if current.Min != nil && (old.Min == nil || *current.Min > *old.Min) {
+		c.report(at, "minimum raised to %g", *current.Min)
+	}
+	if current.Max != nil && (old.Max == nil || *current.Max < *old.Max) {
+		c.report(at, "maximum lowered to %g", *current.Max)
+	}
+	if current.MinLength > old.MinLength {
+		c.report(at, "minimum length raised to %d", current.MinLength)
+	}
+	if current.MaxLength != nil && (old.MaxLength == nil || *current.MaxLength < *old.MaxLength) {
+		c.report(at, "maximum length lowered to %d", *current.MaxLength)
+	}
+	if current.Pattern != "" && current.Pattern != old.Pattern {
+		c.report(at, "pattern changed to %q", current.Pattern)
+	}
+}
+
+// widened reports response values outside the released enum, which
+// clients switching over the known values cannot handle.
+func (c *compatCheck) widened(at string, old, current *openapi3.Schema) {
+	if len(old.Enum) == 0 {
+		return
+	}
+	if len(current.Enum) == 0 {
+		c.report(at, "no longer restricted to %d values", len(old.Enum))
+		return
+	}
+	for _, value := range current.Enum {
+		if !slices.ContainsFunc(old.Enum, func(v any) bool { return fmt.Sprint(v) == fmt.Sprint(value) }) {
+			c.report(at, "value %v may now be returned", value)
+		}
+	}
+}
+
+func typeName(s *openapi3.Schema) string {
+	if types := s.Type.Slice(); len(types) > 0 {
+		return strings.Join(types, "|")
+	}
+	return "any"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
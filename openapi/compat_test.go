@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func loadPaths(t *testing.T, paths string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(
+		`{"openapi": "3.0.3", "info": {"title": "test", "version": "1"}, "paths": ` + paths + `}`))
+	if err != nil {
+		t.Fatalf("load %s: %v", paths, err)
+	}
+	return doc
+}
+
+const ok = `"responses": {"200": {"description": "ok"}}`
+
+// queryParam is GET /items with a single query parameter.
+func queryParam(param string) string {
+	return `{"/items": {"get": {"parameters": [` + param + `], ` + ok + `}}}`
+}
+
+// requestBody is POST /items with a JSON body of the given schema.
+func requestBody(schema string) string {
+	return `{"/items": {"post": {"requestBody": {"content": {"application/json": {"schema": ` + schema + `}}}, ` + ok + `}}}`
+}
+
+// response is GET /items answering 200 with a JSON body of the given schema.
+func response(schema string) string {
+	return `{"/items": {"get": {"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": ` + schema + `}}}}}}}`
+}
+
+func TestBreaking(t *testing.T) {
+	tests := []struct {
+		name              string
+		released, current string
+		want              []string
+	}{
+		{
+			name:     "unchanged",
+			released: response(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+			current:  response(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+		},
+		{
+			name:     "additions clients can ignore",
+			released: `{"/items": {"get": {` + ok + `}}}`,
+			current: `{"/items": {"get": {"parameters": [{"in": "query", "name": "q", "schema": {"type": "string"}}], ` + ok + `},
+				"post": {` + ok + `}}, "/other": {"get": {` + ok + `}}}`,
+		},
+		{
+			name:     "operation removed",
+			released: `{"/items": {"get": {` + ok + `}, "delete": {` + ok + `}}}`,
+			current:  `{"/items": {"get": {` + ok + `}}}`,
+			want:     []string{"DELETE /items: operation removed"},
+		},
+		{
+			name:     "path removed",
+			released: `{"/items": {"get": {` + ok + `}}}`,
+			current:  `{}`,
+			want:     []string{"GET /items: operation removed"},
+		},
+		{
+			name:     "parameter removed",
+			released: queryParam(`{"in": "query", "name": "limit", "schema": {"type": "integer"}}`),
+			current:  `{"/items": {"get": {` + ok + `}}}`,
+			want:     []string{`GET /items: query parameter "limit" removed`},
+		},
+		{
+			name:     "parameter now required",
+			released: queryParam(`{"in": "query", "name": "limit", "schema": {"type": "integer"}}`),
+			current:  queryParam(`{"in": "query", "name": "limit", "required": true, "schema": {"type": "integer"}}`),
+			want:     []string{`GET /items: query parameter "limit" is now required`},
+		},
+		{
+			name:     "new required parameter",
+			released: `{"/items": {"get": {` + ok + `}}}`,
+			current:  queryParam(`{"in": "query", "name": "q", "required": true, "schema": {"type": "string"}}`),
+			want:     []string{`GET /items: query parameter "q" is now required`},
+		},
+		{
+			name:     "parameter type changed",
+			released: queryParam(`{"in": "query", "name": "limit", "schema": {"type": "integer"}}`),
+			current:  queryParam(`{"in": "query", "name": "limit", "schema": {"type": "string"}}`),
+			want:     []string{`GET /items (query parameter "limit"): type changed from integer to string`},
+		},
+		{
+			name:     "parameter range narrowed",
+			released: queryParam(`{"in": "query", "name": "limit", "schema": {"type": "integer", "minimum": 1, "maximum": 200}}`),
+			current:  queryParam(`{"in": "query", "name": "limit", "schema": {"type": "integer", "minimum": 5, "maximum": 100}}`),
+			want: []string{
+				`GET /items (query parameter "limit"): maximum lowered to 100`,
+				`GET /items (query parameter "limit"): minimum raised to 5`,
+			},
+		},
+		{
+			name:     "request body now required",
+			released: `{"/items": {"post": {` + ok + `}}}`,
+			current:  `{"/items": {"post": {"requestBody": {"required": true, "content": {"application/json": {"schema": {"type": "object"}}}}, ` + ok + `}}}`,
+			want:     []string{"POST /items: request body is now required"},
+		},
+		{
+			name:     "request media type removed",
+			released: requestBody(`{"type": "object"}`),
+			current:  `{"/items": {"post": {"requestBody": {"content": {"application/xml": {"schema": {"type": "object"}}}}, ` + ok + `}}}`,
+			want:     []string{"POST /items: request body no longer accepts application/json"},
+		},
+		{
+			name:     "request field now required",
+			released: requestBody(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+			current:  requestBody(`{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`),
+			want:     []string{"POST /items (request body): field /name is now required"},
+		},
+		{
+			name:     "request field no longer nullable",
+			released: requestBody(`{"type": "object", "properties": {"name": {"type": "string", "nullable": true}}}`),
+			current:  requestBody(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+			want:     []string{"POST /items (request body) /name: no longer nullable"},
+		},
+		{
+			name:     "request field becoming nullable is compatible",
+			released: requestBody(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+			current:  requestBody(`{"type": "object", "properties": {"name": {"type": "string", "nullable": true}}}`),
+		},
+		{
+			name:     "request format changed",
+			released: requestBody(`{"type": "string", "format": "date"}`),
+			current:  requestBody(`{"type": "string", "format": "date-time"}`),
+			want:     []string{`POST /items (request body): format changed from "date" to "date-time"`},
+		},
+		{
+			name:     "request enum value removed",
+			released: requestBody(`{"type": "string", "enum": ["a", "b"]}`),
+			current:  requestBody(`{"type": "string", "enum": ["a"]}`),
+			want:     []string{"POST /items (request body): value b no longer accepted"},
+		},
+		{
+			name:     "request enum value added is compatible",
+			released: requestBody(`{"type": "string", "enum": ["a"]}`),
+			current:  requestBody(`{"type": "string", "enum": ["a", "b"]}`),
+		},
+		{
+			name:     "request newly restricted to an enum",
+			released: requestBody(`{"type": "string"}`),
+			current:  requestBody(`{"type": "string", "enum": ["a", "b"]}`),
+			want:     []string{"POST /items (request body): now restricted to 2 values"},
+		},
+		{
+			name:     "request string constraints tightened",
+			released: requestBody(`{"type": "string", "maxLength": 100}`),
+			current:  requestBody(`{"type": "string", "minLength": 1, "maxLength": 50, "pattern": "^[a-z]+$"}`),
+			want: []string{
+				"POST /items (request body): maximum length lowered to 50",
+				"POST /items (request body): minimum length raised to 1",
+				`POST /items (request body): pattern changed to "^[a-z]+$"`,
+			},
+		},
+		{
+			name:     "response constraints tightened are compatible",
+			released: response(`{"type": "string"}`),
+			current:  response(`{"type": "string", "maxLength": 10, "pattern": "^x"}`),
+		},
+		{
+			name:     "response removed",
+			released: `{"/items": {"get": {"responses": {"200": {"description": "ok"}, "404": {"description": "missing"}}}}}`,
+			current:  `{"/items": {"get": {` + ok + `}}}`,
+			want:     []string{"GET /items: response 404 removed"},
+		},
+		{
+			name:     "response media type removed",
+			released: response(`{"type": "object"}`),
+			current:  `{"/items": {"get": {"responses": {"200": {"description": "ok", "content": {"text/plain": {"schema": {"type": "string"}}}}}}}}`,
+			want:     []string{"GET /items: response 200 no longer returns application/json"},
+		},
+		{
+			name:     "response field removed",
+			released: response(`{"type": "object", "properties": {"name": {"type": "string"}, "id": {"type": "string"}}}`),
+			current:  response(`{"type": "object", "properties": {"id": {"type": "string"}}}`),
+			want:     []string{"GET /items (response 200): field /name removed"},
+		},
+		{
+			name:     "response field no longer always present",
+			released: response(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}`),
+			current:  response(`{"type": "object", "properties": {"id": {"type": "string"}}}`),
+			want:     []string{"GET /items (response 200): field /id is no longer always present"},
+		},
+		{
+			name:     "response field now nullable",
+			released: response(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+			current:  response(`{"type": "object", "properties": {"name": {"type": "string", "nullable": true}}}`),
+			want:     []string{"GET /items (response 200) /name: now nullable"},
+		},
+		{
+			name:     "response field no longer nullable is compatible",
+			released: response(`{"type": "object", "properties": {"name": {"type": "string", "nullable": true}}}`),
+			current:  response(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+		},
+		{
+			name:     "response enum value added",
+			released: response(`{"type": "string", "enum": ["a", "b"]}`),
+			current:  response(`{"type": "string", "enum": ["a", "b", "c"]}`),
+			want:     []string{"GET /items (response 200): value c may now be returned"},
+		},
+		{
+			name:     "response enum value removed is compatible",
+			released: response(`{"type": "string", "enum": ["a", "b"]}`),
+			current:  response(`{"type": "string", "enum": ["a"]}`),
+		},
+		{
+			name:     "response enum dropped",
+			released: response(`{"type": "string", "enum": ["a", "b"]}`),
+			current:  response(`{"type": "string"}`),
+			want:     []string{"GET /items (response 200): no longer restricted to 2 values"},
+		},
+		{
+			name:     "array item type changed",
+			released: response(`{"type": "array", "items": {"type": "object", "properties": {"n": {"type": "integer"}}}}`),
+			current:  response(`{"type": "array", "items": {"type": "object", "properties": {"n": {"type": "string"}}}}`),
+			want:     []string{"GET /items (response 200) /[]/n: type changed from integer to string"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Breaking(loadPaths(t, tt.released), loadPaths(t, tt.current))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Breaking =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakingRecursiveSchema(t *testing.T) {
+	doc := func(childType string) string {
+		return `{"openapi": "3.0.3", "info": {"title": "test", "version": "1"},
+			"components": {"schemas": {"Node": {"type": "object", "properties": {
+				"name": {"type": "` + childType + `"},
+				"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}},
+			"paths": {"/nodes": {"get": {"responses": {"200": {"description": "ok",
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}}}}}}`
+	}
+	load := func(data string) *openapi3.T {
+		t.Helper()
+		d, err := openapi3.NewLoader().LoadFromData([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	got := Breaking(load(doc("string")), load(doc("integer")))
+	want := []string{"GET /nodes (response 200) /name: type changed from string to integer"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Breaking = %q, want %q", got, want)
+	}
+}
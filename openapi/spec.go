@@ -0,0 +1,161 @@
+// Package openapi assembles the API description from openapi.yaml and the
+// per-resource fragments in resources/. A fragment is an OpenAPI document
+// holding only paths and components; it may refer to components of the base
+// document or of other fragments with local "#/components/..." refs, which
+// resolve once everything is merged.
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml all:resources
+var files embed.FS
+
+// Merged returns the base document with every fragment merged in, as YAML.
+// Fragments are applied in file name order. A path declared twice is an
+// error, as is a component declared twice with different definitions;
+// repeating an identical component is allowed so fragments can stand alone.
+func Merged() ([]byte, error) {
+	return merge(files)
+}
+
+// Load returns the merged document, with refs resolved and validated.
+func Load() (*openapi3.T, error) {
+	data, err := Merged()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("load merged spec: %w", err)
+	}
+	if err := doc.Validate(openapi3.NewLoader().Context); err != nil {
+		return nil, fmt.Errorf("merged spec is invalid: %w", err)
+	}
+	return doc, nil
+}
+
+func merge(fsys fs.FS) ([]byte, error) {
+	base, err := readDocument(fsys, "openapi.yaml")
+	if err != nil {
+		return nil, err
+	}
+	fragments, err := fs.Glob(fsys, "resources/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fragments)
+	for _, name := range fragments {
+		fragment, err := readDocument(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := mergeFragment(base, fragment); err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(name), err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(base); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readDocument parses name and returns its top-level mapping node.
+func readDocument(fsys fs.FS, name string) (*yaml.Node, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: not a YAML mapping", name)
+	}
+	return doc.Content[0], nil
+}
+
+func mergeFragment(base, fragment *yaml.Node) error {
+	for i := 0; i < len(fragment.Content); i += 2 {
+		key, value := fragment.Content[i].Value, fragment.Content[i+1]
+		switch key {
+		case "paths":
+			if err := mergeMapping(child(base, key), value, "path", false); err != nil {
+				return err
+			}
+		case "components":
+			components := child(base, key)
+			for j := 0; j < len(value.Content); j += 2 {
+				section := value.Content[j].Value
+				if err := mergeMapping(child(components, section), value.Content[j+1], "components."+section, true); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("fragments may only declare paths and components, not %s", key)
+		}
+	}
+	return nil
+}
+
+// mergeMapping adds the entries of src to dst. An entry already in dst is a
+// conflict unless allowEqual is set and both definitions are the same.
+func mergeMapping(dst, src *yaml.Node, kind string, allowEqual bool) error {
+	if src.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s entries must be a mapping", kind)
+	}
+	for i := 0; i < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		if existing := lookup(dst, key.Value); existing != nil {
+			if allowEqual && equalNodes(existing, value) {
+				continue
+			}
+			return fmt.Errorf("%s %q is already defined", kind, key.Value)
+		}
+		dst.Content = append(dst.Content, key, value)
+	}
+	return nil
+}
+
+// child returns the mapping stored under key in m, adding an empty one if
+// there is none.
+func child(m *yaml.Node, key string) *yaml.Node {
+	if value := lookup(m, key); value != nil {
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+func lookup(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func equalNodes(a, b *yaml.Node) bool {
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,103 @@
+// Package problem implements problem details for HTTP APIs (RFC 7807), the
+// body of every error response the API sends. It mirrors the Problem
+// schema in openapi/openapi.yaml.
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ContentType is the media type of a problem details body.
+const ContentType = "application/problem+json"
+
+// Problem describes why a request failed. It implements error so it can be
+// returned from deep inside a handler and written out unchanged.
+type Problem struct {
+	// Type is a URI identifying the kind of problem; "about:blank" means
+	// the status code says it all.
+	Type string
+	// Title is a short summary of the kind of problem.
+	Title  string
+	Status int
+	// Detail explains this occurrence of the problem.
+	Detail string
+	// Instance identifies this occurrence; Write fills in the request path.
+	Instance string
+	// Extensions are additional members, such as the list of validation
+	// failures, serialized next to the standard ones.
+	Extensions map[string]any
+}
+
+// New returns a problem of type about:blank for status, titled with the
+// status text.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Newf is New with a formatted detail.
+func Newf(status int, format string, args ...any) *Problem {
+	return New(status, fmt.Sprintf(format, args...))
+}
+
+// With adds the extension member key to p and returns p.
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = map[string]any{}
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// Members returns the JSON object members of p, omitting empty optional
+// ones. Extensions never override the standard members.
+func (p *Problem) Members() map[string]any {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return members
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Members())
+}
+
+// Write sends p as the response to r. Instance defaults to the request
+// path; p itself is not modified.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) error {
+	reported := *p
+	if reported.Instance == "" && r != nil {
+		reported.Instance = r.URL.Path
+	}
+	body, err := json.Marshal(&reported)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(reported.Status)
+	_, err = w.Write(body)
+	return err
+}
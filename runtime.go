@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"go.uber.org/automaxprocs/maxprocs"
+)
+
+// memoryLimitRatio is the share of the container memory limit handed to the
+// Go runtime as its soft limit, leaving headroom for non-heap memory.
+const memoryLimitRatio = 0.9
+
+// configureRuntime sizes GOMAXPROCS to the container CPU quota and, unless
+// GOMEMLIMIT is set explicitly, derives a soft memory limit from the
+// container memory limit. The effective settings are logged.
+func configureRuntime() {
+	if _, err := maxprocs.Set(maxprocs.Logger(log.Printf)); err != nil {
+		log.Printf("Failed to set GOMAXPROCS from CPU quota: %v", err)
+	}
+
+	if os.Getenv("GOMEMLIMIT") == "" {
+		if limit, ok := containerMemoryLimit(); ok {
+			debug.SetMemoryLimit(int64(float64(limit) * memoryLimitRatio))
+		}
+	}
+
+	memLimit := "none"
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		memLimit = strconv.FormatInt(limit, 10)
+	}
+	log.Printf("Runtime: %s GOMAXPROCS=%d NumCPU=%d GOMEMLIMIT=%s", runtime.Version(), runtime.GOMAXPROCS(0), runtime.NumCPU(), memLimit)
+}
+
+// containerMemoryLimit reads the memory limit of the current cgroup, trying
+// cgroup v2 first and then v1. It reports false when no limit is set.
+func containerMemoryLimit() (int64, bool) {
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "max" {
+			return 0, false
+		}
+		limit, err := strconv.ParseInt(value, 10, 64)
+		// cgroup v1 reports "unlimited" as a page-aligned value near MaxInt64.
+		if err != nil || limit <= 0 || limit >= math.MaxInt64/2 {
+			return 0, false
+		}
+		return limit, true
+	}
+	return 0, false
+}